@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	registerParser("ansible", "hostnames of an INI or YAML Ansible inventory, of every group or of --ansible-group", (*fileSet).parseAnsible)
+}
+
+// inventoryHost is a host of an Ansible inventory and the line it was listed on.
+type inventoryHost struct {
+	name string
+	line int
+}
+
+// inventoryGroup is a group of an Ansible inventory: the hosts listed in it and the names of its child groups.
+type inventoryGroup struct {
+	hosts    []inventoryHost
+	children []string
+}
+
+// ansibleInventory is the groups of an Ansible inventory by name, along with every host, in the order they are listed.
+type ansibleInventory struct {
+	groups map[string]*inventoryGroup
+	hosts  []inventoryHost
+}
+
+// hostRangePattern matches a range in an inventory host pattern, like [01:50], [a:f], or [1:10:2] with a stride.
+var hostRangePattern = regexp.MustCompile(`\[([0-9]+|[a-zA-Z]):([0-9]+|[a-zA-Z])(?::([0-9]+))?\]`)
+
+/*
+parseAnsible reads an Ansible inventory from r, in the INI or YAML format, and adds the hosts of the group given with
+--ansible-group to the set, or every host of the inventory if none is. A group's hosts include those of its children.
+Host ranges, like web[01:03].example.com, are expanded, and ports are removed. The format is YAML if the inventory is a
+YAML map, and INI otherwise. Returns an error if the inventory can't be read, or if the group isn't in it.
+*/
+func (fs *fileSet) parseAnsible(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	inv := &ansibleInventory{groups: map[string]*inventoryGroup{"all": {}, "ungrouped": {}}}
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) == nil && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		err = inv.readYAML(doc.Content[0])
+	} else {
+		err = inv.readINI(data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse ansible inventory %s: %w", fs.path, err)
+	}
+	group := firstNonEmpty(ansibleGroup, "all")
+	if _, ok := inv.groups[group]; !ok {
+		return fmt.Errorf("ansible inventory %s has no group %s", fs.path, group)
+	}
+	for _, host := range inv.members(group, map[string]bool{}) {
+		fs.line = host.line
+		fs.add(host.name, nil)
+	}
+	return nil
+}
+
+// group returns the group called name, adding it if it isn't in the inventory yet.
+func (inv *ansibleInventory) group(name string) *inventoryGroup {
+	g, ok := inv.groups[name]
+	if !ok {
+		g = &inventoryGroup{}
+		inv.groups[name] = g
+	}
+	return g
+}
+
+// addHosts adds the hosts matching pattern, listed on line, to the group called name.
+func (inv *ansibleInventory) addHosts(name, pattern string, line int) error {
+	// a trailing colon and digits are a port, unless the host contains other colons, like an IPv6 address
+	if i := strings.LastIndex(pattern, ":"); i >= 0 {
+		_, err := strconv.Atoi(pattern[i+1:])
+		if err == nil && !strings.Contains(hostRangePattern.ReplaceAllString(pattern[:i], ""), ":") {
+			pattern = pattern[:i]
+		}
+	}
+	hosts, err := expandHostPattern(pattern)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", line, err)
+	}
+	g := inv.group(name)
+	for _, host := range hosts {
+		g.hosts = append(g.hosts, inventoryHost{host, line})
+		inv.hosts = append(inv.hosts, inventoryHost{host, line})
+	}
+	return nil
+}
+
+/*
+members returns the hosts of the group called name and of its children, recursively. The all group holds every host.
+Groups in visited are skipped, so a cycle of child groups ends.
+*/
+func (inv *ansibleInventory) members(name string, visited map[string]bool) []inventoryHost {
+	if name == "all" {
+		return inv.hosts
+	}
+	if visited[name] {
+		return nil
+	}
+	visited[name] = true
+	g := inv.group(name)
+	hosts := append([]inventoryHost(nil), g.hosts...)
+	for _, child := range g.children {
+		hosts = append(hosts, inv.members(child, visited)...)
+	}
+	return hosts
+}
+
+/*
+readINI reads an inventory in the INI format: [group] sections listing a host, followed by its variables, per line,
+[group:children] sections listing child groups, and [group:vars] sections, which are skipped. Hosts listed before the
+first section are ungrouped. Lines starting with # or ; are comments.
+*/
+func (inv *ansibleInventory) readINI(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	group, kind := "ungrouped", ""
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group, kind, _ = strings.Cut(line[1:len(line)-1], ":")
+			if kind != "" && kind != "children" && kind != "vars" {
+				return fmt.Errorf("line %d: invalid section %s", n, line)
+			}
+			inv.group(group)
+			continue
+		}
+		name := strings.Fields(line)[0]
+		switch kind {
+		case "":
+			if err := inv.addHosts(group, name, n); err != nil {
+				return err
+			}
+		case "children":
+			inv.group(group).children = append(inv.group(group).children, name)
+			inv.group(name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+/*
+readYAML reads an inventory in the YAML format, a map of groups, each of which may have a map of hosts, a map of child
+groups defined the same way, and vars, which are skipped.
+*/
+func (inv *ansibleInventory) readYAML(node *yaml.Node) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if err := inv.readYAMLGroup(node.Content[i].Value, node.Content[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readYAMLGroup reads the hosts and child groups of the group called name from node. An empty group is null.
+func (inv *ansibleInventory) readYAMLGroup(name string, node *yaml.Node) error {
+	g := inv.group(name)
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if value.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			entry := value.Content[j]
+			switch key.Value {
+			case "hosts":
+				if err := inv.addHosts(name, entry.Value, entry.Line); err != nil {
+					return err
+				}
+			case "children":
+				g.children = append(g.children, entry.Value)
+				if err := inv.readYAMLGroup(entry.Value, value.Content[j+1]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+expandHostPattern returns the hosts matched by an inventory host pattern, expanding each of its ranges: numeric ones,
+like [01:50], which keep the zero padding of their start, and alphabetic ones, like [a:f], either with an optional
+stride, like [1:10:2]. A pattern without ranges is the host itself.
+*/
+func expandHostPattern(pattern string) ([]string, error) {
+	loc := hostRangePattern.FindStringSubmatchIndex(pattern)
+	if loc == nil {
+		return []string{pattern}, nil
+	}
+	start, end := pattern[loc[2]:loc[3]], pattern[loc[4]:loc[5]]
+	stride := 1
+	if loc[6] >= 0 {
+		stride, _ = strconv.Atoi(pattern[loc[6]:loc[7]])
+	}
+	if stride < 1 {
+		return nil, fmt.Errorf("invalid stride in host range %s", pattern[loc[0]:loc[1]])
+	}
+	var values []string
+	if from, err := strconv.Atoi(start); err == nil {
+		to, err := strconv.Atoi(end)
+		if err != nil || to < from {
+			return nil, fmt.Errorf("invalid host range %s", pattern[loc[0]:loc[1]])
+		}
+		for i := from; i <= to; i += stride {
+			values = append(values, fmt.Sprintf("%0*d", len(start), i))
+		}
+	} else {
+		if len(end) != 1 || end[0] < start[0] {
+			return nil, fmt.Errorf("invalid host range %s", pattern[loc[0]:loc[1]])
+		}
+		for c := int(start[0]); c <= int(end[0]); c += stride {
+			values = append(values, string(rune(c)))
+		}
+	}
+	rest, err := expandHostPattern(pattern[loc[1]:])
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, v := range values {
+		for _, r := range rest {
+			hosts = append(hosts, pattern[:loc[0]]+v+r)
+		}
+	}
+	return hosts, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/*
+openAPI reads values from a paginated JSON REST API and returns them one per line. target is the URL without its
+scheme, which defaults to https, e.g. "api.example.com/v1/users?active=true", or a full http:// or https:// URL. The
+values are selected from each response by the api-json-path flag. Requests are authenticated with the api-token flag,
+or API_TOKEN, as a bearer token, and with any api-header flags, whose values may reference environment variables.
+
+Pages are followed according to the api-paginate flag:
+  - link follows the rel="next" URL of the Link header, which also covers unpaginated APIs
+  - page increments the api-param query parameter, "page" by default, from 1 until a page selects no values
+  - cursor sets the api-param query parameter, "cursor" by default, to the value at the api-cursor path of the previous
+    response until it is empty
+*/
+func openAPI(ctx context.Context, target string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "https://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api input: %w", err)
+	}
+	if apiPaginate == "cursor" && apiCursor == "" {
+		return nil, fmt.Errorf("--api-cursor is required with --api-paginate cursor")
+	}
+	// the query parameter defaults to the name of the pagination strategy, i.e. page or cursor
+	param := apiParam
+	if param == "" && apiPaginate != "link" {
+		param = apiPaginate
+	}
+
+	var values []string
+	for page := 1; ; page++ {
+		if apiPaginate == "page" {
+			q := u.Query()
+			q.Set(param, strconv.Itoa(page))
+			u.RawQuery = q.Encode()
+		}
+		resp, doc, err := apiRequest(ctx, u.String())
+		if err != nil {
+			return nil, err
+		}
+		selected, err := evalJSONPath(doc, apiJSONPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range selected {
+			if s := jsonScalar(v); s != "" {
+				values = append(values, s)
+			}
+		}
+
+		next := ""
+		switch apiPaginate {
+		case "link":
+			next = nextLink(resp)
+		case "page":
+			if len(selected) > 0 {
+				next = u.String()
+			}
+		case "cursor":
+			cursors, err := evalJSONPath(doc, apiCursor)
+			if err != nil {
+				return nil, err
+			}
+			if len(cursors) > 0 && jsonScalar(cursors[0]) != "" {
+				q := u.Query()
+				q.Set(param, jsonScalar(cursors[0]))
+				u.RawQuery = q.Encode()
+				next = u.String()
+			}
+		}
+		if next == "" {
+			l.Debug().Str("url", target).Int("pages", page).Int("values", len(values)).Msg("read api values")
+			return linesReader(values), nil
+		}
+		if u, err = url.Parse(next); err != nil {
+			return nil, fmt.Errorf("invalid next page url: %w", err)
+		}
+	}
+}
+
+// apiRequest makes an authenticated GET request to rawURL and decodes its JSON body.
+func apiRequest(ctx context.Context, rawURL string) (*http.Response, any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create api request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := firstNonEmpty(apiToken, os.Getenv("API_TOKEN")); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if err := setHeaders(req, apiHeaders); err != nil {
+		return nil, nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query api: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("api request %s failed: %s", rawURL, resp.Status)
+	}
+	doc, err := decodeJSON(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode api response: %w", err)
+	}
+	return resp, doc, nil
+}
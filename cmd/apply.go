@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+	"github.com/spf13/cobra"
+)
+
+var dryRun bool
+
+var applyPatchCmd = &cobra.Command{
+	Use:   "apply-patch [patch.json] [target]",
+	Short: "Apply a set-patch written by --emit-patch to a file",
+	Long: `apply-patch applies a set-patch written by --emit-patch to a target file, completing the sync workflow. Lines
+whose element is listed for removal are deleted and lines listed for addition are appended if their element isn't
+already present, so applying a patch twice has no further effect. Comments, blank lines, and the order of the remaining
+lines are preserved.
+
+Elements are matched the same way they were compared, so pass the normalization flags used when emitting the patch,
+like --ignore-fqdn, --replace, or --field. Elements listed for removal that match no line of the target are reported as
+warnings. Compressed targets are only read with --dry-run, since they would be written back uncompressed.`,
+	Args: cobra.ExactArgs(2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return prepareNormalization(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyPatch(args[0], args[1]); err != nil {
+			l.Fatal().Err(err).Send()
+		}
+	},
+}
+
+/*
+applyPatch applies the set-patch at patchPath to the file at target, replacing it unless dryRun is set, in which case
+the patched content is printed instead. Lines starting with '#' or ';' and blank lines are kept as they are, as is the
+header row if hasHeader is set. Elements listed for removal that match no line are logged as warnings. Returns an error
+if target is compressed and dryRun isn't set, rather than replacing it with its uncompressed content.
+*/
+func applyPatch(patchPath, target string) error {
+	data, err := os.ReadFile(patchPath)
+	if err != nil {
+		return fmt.Errorf("failed to read patch: %w", err)
+	}
+	var p setPatch
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("failed to decode patch: %w", err)
+	}
+	remove := map[string]bool{}
+	for _, element := range p.Remove {
+		remove[element] = true
+	}
+
+	file, err := openFile(target)
+	if err != nil {
+		return err
+	}
+	if d, ok := file.(*decompressedReader); ok && d.format != "" && !dryRun {
+		file.Close()
+		return fmt.Errorf("can't apply a patch to %s compressed file %s, decompress it or use --dry-run", d.format, target)
+	}
+	fs := newFileSet(target)
+	var lines []string
+	present := map[string]bool{}
+	matched := map[string]bool{}
+	removed := 0
+	header := !hasHeader
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// the header row is kept as it is, and names the column compared as the element
+		if !header && strings.TrimSpace(line) != "" && !isComment(line) && !fs.set.IsComment(line) {
+			header = true
+			if columnName != "" {
+				if err := fs.findColumn(line); err != nil {
+					file.Close()
+					return err
+				}
+			}
+			lines = append(lines, line)
+			continue
+		}
+		if key, ok := fs.normalizedKey(line); ok && !isComment(line) {
+			if remove[key] {
+				matched[key] = true
+				removed++
+				continue
+			}
+			present[key] = true
+		}
+		lines = append(lines, line)
+	}
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	for _, element := range p.Remove {
+		if !matched[element] {
+			l.Warn().Str("target", target).Str("element", element).Msg("element to remove matches no line")
+		}
+	}
+	added := 0
+	for _, line := range p.Add {
+		key, ok := fs.normalizedKey(line)
+		if !ok || present[key] {
+			continue
+		}
+		present[key] = true
+		lines = append(lines, line)
+		added++
+	}
+	l.Info().Str("target", target).Int("added", added).Int("removed", removed).Msg("applied patch")
+
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		content += "\n"
+	}
+	if dryRun {
+		_, err := os.Stdout.WriteString(content)
+		return err
+	}
+	return replaceFile(target, content)
+}
+
+/*
+normalizedKey returns the element a line of fs is compared as, rewritten, split, and normalized the same way as by
+scanLines, or false if the line is skipped, e.g. because it's blank, a comment, or has fewer fields.
+*/
+func (fs *fileSet) normalizedKey(line string) (string, bool) {
+	if fs.set.IsComment(line) {
+		return "", false
+	}
+	line, ok := fs.set.RewriteLine(line)
+	if !ok || strings.TrimSpace(line) == "" {
+		return "", false
+	}
+	element, _, ok := diffit.SplitFields(line, delimiter, fs.selectedField())
+	if !ok {
+		return "", false
+	}
+	return fs.set.Normalize(element)
+}
+
+// isComment reports whether line is a comment, starting with '#' or ';'.
+func isComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";")
+}
+
+// replaceFile atomically replaces the file at path with content, keeping its permissions.
+func replaceFile(path, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(applyPatchCmd)
+	applyPatchCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the patched file instead of replacing it")
+	// the flags that affect how elements are normalized, so they match the ones the patch was emitted with
+	addNormalizationFlags(applyPatchCmd)
+}
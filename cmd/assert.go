@@ -0,0 +1,41 @@
+package cmd
+
+import "fmt"
+
+/*
+checkContainment asserts that fileA is a subset of fileB, if checkSubset is set, or a superset of it, if checkSuperset
+is set. The elements violating the assertion, those of fileA missing from fileB for a subset, or those of fileB missing
+from fileA for a superset, are stored in setAB, so they are the only results written. If multiset is set, an element
+also violates the assertion if it is seen more often in the contained file, and is counted by the excess.
+*/
+func (r *results) checkContainment() {
+	r.operation = "subset"
+	contained, container := &r.fileSetA, &r.fileSetB
+	if checkSuperset {
+		r.operation = "superset"
+		contained, container = container, contained
+	}
+	if multiset {
+		r.counts = map[string]int{}
+		r.addExcess(&r.setAB, contained, container)
+		return
+	}
+	for _, element := range contained.set.Keys() {
+		if !container.set.Contains(element) {
+			r.setAB.Add(element)
+		}
+	}
+}
+
+// containmentError returns an error describing the failed assertion of checkContainment, or nil if it holds.
+func (r *results) containmentError() error {
+	if r.operation != "subset" && r.operation != "superset" || r.setAB.Size() == 0 {
+		return nil
+	}
+	missingFrom := r.fileSetB.path
+	if r.operation == "superset" {
+		missingFrom = r.fileSetA.path
+	}
+	return fmt.Errorf("%s is not a %s of %s, elements missing from %s: %d", r.fileSetA.path, r.operation, r.fileSetB.path,
+		missingFrom, r.setAB.Size())
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ec2APIVersion is the version of the EC2 query API used to describe instances.
+const ec2APIVersion = "2016-11-15"
+
+// ec2Tag is a tag of an EC2 instance in a DescribeInstances response.
+type ec2Tag struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+// ec2Instance is the subset of an EC2 instance in a DescribeInstances response used as set elements.
+type ec2Instance struct {
+	InstanceID     string   `xml:"instanceId"`
+	PrivateIP      string   `xml:"privateIpAddress"`
+	PublicIP       string   `xml:"ipAddress"`
+	PrivateDNSName string   `xml:"privateDnsName"`
+	PublicDNSName  string   `xml:"dnsName"`
+	State          string   `xml:"instanceState>name"`
+	Tags           []ec2Tag `xml:"tagSet>item"`
+}
+
+// describeInstancesResponse is a page of an EC2 DescribeInstances response.
+type describeInstancesResponse struct {
+	Reservations []struct {
+		Instances []ec2Instance `xml:"instancesSet>item"`
+	} `xml:"reservationSet>item"`
+	NextToken string `xml:"nextToken"`
+}
+
+// tag returns the value of the instance's tag named key, or an empty string if it isn't set.
+func (i ec2Instance) tag(key string) string {
+	for _, t := range i.Tags {
+		if t.Key == key {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+/*
+attribute returns the value of the instance attribute named attr: name (the Name tag), instance-id, private-ip,
+public-ip, private-dns, public-dns, or tag:KEY for any other tag. It returns an error for an unknown attribute.
+*/
+func (i ec2Instance) attribute(attr string) (string, error) {
+	switch attr {
+	case "name":
+		return i.tag("Name"), nil
+	case "instance-id":
+		return i.InstanceID, nil
+	case "private-ip":
+		return i.PrivateIP, nil
+	case "public-ip":
+		return i.PublicIP, nil
+	case "private-dns":
+		return i.PrivateDNSName, nil
+	case "public-dns":
+		return i.PublicDNSName, nil
+	}
+	if key, ok := strings.CutPrefix(attr, "tag:"); ok {
+		return i.tag(key), nil
+	}
+	return "", fmt.Errorf("unknown ec2 attribute: %s", attr)
+}
+
+/*
+openAWS lists EC2 instances and returns the chosen attribute of each, one per line. target is "ec2" optionally followed
+by "/ATTRIBUTE", defaulting to the Name tag, and a "?state=running" query to only include instances in that state.
+Instances without a value for the attribute are skipped. Credentials and region are loaded by the AWS SDK from the
+aws-profile and aws-region flags, falling back to the standard AWS environment variables and shared config files, so
+SSO, role assumption, and instance roles work like they do with the AWS CLI.
+*/
+func openAWS(ctx context.Context, target string) (io.ReadCloser, error) {
+	u, err := url.Parse("aws://" + target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aws input: %w", err)
+	}
+	if u.Host != "ec2" {
+		return nil, fmt.Errorf("unsupported aws service: %s, only ec2 is supported", u.Host)
+	}
+	attr := strings.Trim(u.Path, "/")
+	if attr == "" {
+		attr = "name"
+	}
+	if _, err := (ec2Instance{}).attribute(attr); err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := describeInstances(ctx, cfg, u.Query().Get("state"))
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, instance := range instances {
+		if v, _ := instance.attribute(attr); v != "" {
+			values = append(values, v)
+		}
+	}
+	l.Debug().Str("region", cfg.Region).Str("attribute", attr).Int("instances", len(instances)).
+		Msg("described ec2 instances")
+	return linesReader(values), nil
+}
+
+/*
+loadAWSConfig loads the AWS configuration with the SDK, using the profile and region given with the aws-profile and
+aws-region flags, if set. It returns an error if the configuration can't be loaded or no region is set.
+*/
+func loadAWSConfig(ctx context.Context) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if awsProfile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(awsProfile))
+	}
+	if awsRegion != "" {
+		opts = append(opts, config.WithRegion(awsRegion))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	if cfg.Region == "" {
+		return cfg, fmt.Errorf("no aws region set, use --aws-region or AWS_REGION")
+	}
+	return cfg, nil
+}
+
+/*
+describeInstances returns every EC2 instance in the region of cfg, following pagination. If state is set, only
+instances in that state are returned. Requests are signed by the SDK with the credentials of cfg, and made with
+httpClient, so they are rate limited like those of other sources.
+*/
+func describeInstances(ctx context.Context, cfg aws.Config, state string) ([]ec2Instance, error) {
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws credentials: %w", err)
+	}
+	endpoint := firstNonEmpty(os.Getenv("AWS_ENDPOINT_URL_EC2"), aws.ToString(cfg.BaseEndpoint),
+		"https://ec2."+cfg.Region+".amazonaws.com")
+	signer := v4.NewSigner()
+	var instances []ec2Instance
+	nextToken := ""
+	for {
+		form := url.Values{
+			"Action":     {"DescribeInstances"},
+			"Version":    {ec2APIVersion},
+			"MaxResults": {"1000"},
+		}
+		if state != "" {
+			form.Set("Filter.1.Name", "instance-state-name")
+			form.Set("Filter.1.Value.1", state)
+		}
+		if nextToken != "" {
+			form.Set("NextToken", nextToken)
+		}
+		body := form.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ec2 request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+		bodyHash := sha256.Sum256([]byte(body))
+		err = signer.SignHTTP(ctx, creds, req, hex.EncodeToString(bodyHash[:]), "ec2", cfg.Region, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign ec2 request: %w", err)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe ec2 instances: %w", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ec2 response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to describe ec2 instances: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+		}
+		var page describeInstancesResponse
+		if err := xml.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("failed to decode ec2 response: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+		if page.NextToken == "" {
+			return instances, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// firstNonEmpty returns the first of values that isn't empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// binarySniffLength is the number of bytes peeked at when detecting binary inputs.
+const binarySniffLength = 4096
+
+// binaryThreshold is the share of invalid UTF-8 and control bytes above which an input is considered binary.
+const binaryThreshold = 0.1
+
+/*
+isBinary reports whether head, the first bytes of an input, looks like binary data rather than text. Binary data
+contains a NUL byte, or more than binaryThreshold of it is invalid UTF-8 or control characters other than whitespace.
+*/
+func isBinary(head []byte) bool {
+	suspicious := 0
+	for i := 0; i < len(head); {
+		r, size := utf8.DecodeRune(head[i:])
+		switch {
+		case r == 0:
+			return true
+		case r == utf8.RuneError && size == 1:
+			// a rune cut off at the end of head is not invalid
+			if !utf8.FullRune(head[i:]) {
+				return float64(suspicious) > binaryThreshold*float64(len(head))
+			}
+			suspicious++
+		case unicode.IsControl(r) && !unicode.IsSpace(r):
+			suspicious += size
+		}
+		i += size
+	}
+	return float64(suspicious) > binaryThreshold*float64(len(head))
+}
+
+/*
+checkBinary returns the reader to parse r from, after detecting whether r holds binary data. Text inputs are returned as
+is. For binary inputs, it returns r with its invalid UTF-8 and control bytes escaped if forceBinary is set, or nil,
+after logging a warning, if skipBinary is set. Otherwise it returns an error.
+*/
+func (fs *fileSet) checkBinary(r *bufio.Reader) (*bufio.Reader, error) {
+	head, _ := r.Peek(binarySniffLength)
+	if !isBinary(head) {
+		return r, nil
+	}
+	switch {
+	case forceBinary:
+		l.Warn().Str("path", fs.path).Msg("input looks binary, comparing its lines with non-text bytes escaped")
+		return bufio.NewReader(transform.NewReader(r, binaryEscaper{})), nil
+	case skipBinary:
+		l.Warn().Str("path", fs.path).Msg("input looks binary, skipping it")
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%s looks like a binary file, pass --skip-binary to skip it or --force-binary to compare it anyway", fs.path)
+	}
+}
+
+/*
+binaryEscaper is a transform.Transformer that replaces each byte of invalid UTF-8 and control characters, other than
+tabs, carriage returns, and newlines, with a \xNN escape, so binary inputs can be compared and printed as text.
+*/
+type binaryEscaper struct {
+	transform.NopResetter
+}
+
+// Transform implements transform.Transformer.
+func (binaryEscaper) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError && size == 1 && !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		out := src[nSrc : nSrc+size]
+		if (r == utf8.RuneError && size == 1) || (unicode.IsControl(r) && r != '\t' && r != '\r' && r != '\n') {
+			var escaped []byte
+			for _, b := range out {
+				escaped = fmt.Appendf(escaped, `\x%02x`, b)
+			}
+			out = escaped
+		}
+		if nDst+len(out) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], out)
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"math"
+	"os"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+)
+
+const (
+	// bloomFalsePositives is the rate of elements the bloom filter wrongly reports as possibly in the smaller input.
+	bloomFalsePositives = 0.01
+	// bloomMaxSize is the size of the largest local file --streaming prefilters the other input against automatically.
+	bloomMaxSize = 64 << 20
+	// bloomRatio is how many times larger than the smaller input the other must be to be prefiltered automatically.
+	bloomRatio = 64
+)
+
+// bloomFilter is a bloom filter of strings, which reports whether a string is possibly, or definitely not, in a set.
+type bloomFilter struct {
+	bits   []uint64
+	hashes uint64
+	seed   maphash.Seed
+}
+
+// newBloomFilter returns an empty bloom filter sized for n strings with a false positive rate of bloomFalsePositives.
+func newBloomFilter(n int) *bloomFilter {
+	m := math.Ceil(-float64(max(n, 1)) * math.Log(bloomFalsePositives) / (math.Ln2 * math.Ln2))
+	k := max(1, math.Round(m/float64(max(n, 1))*math.Ln2))
+	return &bloomFilter{bits: make([]uint64, (uint64(m)+63)/64), hashes: uint64(k), seed: maphash.MakeSeed()}
+}
+
+// positions calls fn with each bit of s, derived from two halves of a single hash, as described by Kirsch and
+// Mitzenmacher.
+func (b *bloomFilter) positions(s string, fn func(word uint64, bit uint64) bool) {
+	h := maphash.String(b.seed, s)
+	h1, h2 := h&math.MaxUint32, h>>32|1
+	m := uint64(len(b.bits)) * 64
+	for i := uint64(0); i < b.hashes; i++ {
+		pos := (h1 + i*h2) % m
+		if !fn(pos/64, uint64(1)<<(pos%64)) {
+			return
+		}
+	}
+}
+
+// add adds s to the filter.
+func (b *bloomFilter) add(s string) {
+	b.positions(s, func(word, bit uint64) bool {
+		b.bits[word] |= bit
+		return true
+	})
+}
+
+// mayContain reports whether s is possibly in the filter. If it returns false, s was definitely never added.
+func (b *bloomFilter) mayContain(s string) bool {
+	found := true
+	b.positions(s, func(word, bit uint64) bool {
+		found = b.bits[word]&bit != 0
+		return found
+	})
+	return found
+}
+
+/*
+prefilter holds the elements of the smaller input of a prefiltered comparison. Elements of the larger input are looked
+up in its bloom filter first, so the set is only consulted for the few that may be in it.
+*/
+type prefilter struct {
+	bloom *bloomFilter
+	set   *diffit.Set
+	seen  map[string]bool // elements of set also found in the larger input
+}
+
+// newPrefilter returns a prefilter of the elements of set.
+func newPrefilter(set *diffit.Set) *prefilter {
+	p := &prefilter{bloom: newBloomFilter(set.Len()), set: set, seen: map[string]bool{}}
+	for _, element := range set.Keys() {
+		p.bloom.add(element)
+	}
+	return p
+}
+
+// contains reports whether element is in the smaller input, recording it as seen in the larger one if it is.
+func (p *prefilter) contains(element string) bool {
+	if !p.bloom.mayContain(element) || !p.set.Contains(element) {
+		return false
+	}
+	p.seen[element] = true
+	return true
+}
+
+/*
+smallerInput returns the index of the input named by args, 0 for fileA or 1 for fileB, that the other should be
+prefiltered against. With --bloom, that is the smaller local file, and inputs whose size is unknown, like stdin or URLs,
+are assumed to be larger. Otherwise, it is only returned if it is a local file of at most bloomMaxSize and the other is
+a local file at least bloomRatio times larger, so memory isn't spent on a filter that would barely filter anything.
+*/
+func smallerInput(args []string) (int, bool) {
+	sizes := make([]int64, 2)
+	for i, path := range args[:2] {
+		sizes[i] = -1
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			sizes[i] = info.Size()
+		}
+	}
+	small := 0
+	if sizes[0] < 0 || (sizes[1] >= 0 && sizes[1] < sizes[0]) {
+		small = 1
+	}
+	if bloom {
+		if sizes[small] < 0 {
+			return 0, true
+		}
+		return small, true
+	}
+	large := sizes[1-small]
+	if sizes[small] < 0 || large < 0 || sizes[small] > bloomMaxSize || large < sizes[small]*bloomRatio {
+		return 0, false
+	}
+	return small, true
+}
+
+/*
+streamPrefiltered reads small into memory and spills it to a sorted run file in smallDir, then streams large to sorted
+run files in largeDir, holding back its elements that are in small. Those are known to be in both, so instead of
+spilling them as they are read, they are written once as a last run of large, and only the elements of large that aren't
+in small are ever sorted. The runs of both can then be merge-joined like any other streamed inputs.
+*/
+func streamPrefiltered(ctx context.Context, small, large *fileSet, smallDir, largeDir string) error {
+	// the smaller input is held in memory as a whole, so it is read without spilling
+	if err := small.readStream(ctx); err != nil {
+		return err
+	}
+	p := newPrefilter(small.set)
+	l.Debug().Str("path", small.path).Int("elements", small.set.Len()).Msg("prefiltering against bloom filter")
+	small.runs = &streamRuns{dir: smallDir}
+	small.spill()
+	large.prefilter = p
+	if err := large.streamToRuns(ctx, largeDir); err != nil {
+		return err
+	}
+	large.prefilter = nil
+	for element := range p.seen {
+		large.set.Insert(element, nil)
+	}
+	large.spill()
+	return errors.Join(small.runs.err, large.runs.err)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+cacheFile returns the path of the cached copy of the input at path. It is named after a hash of the path and the flags
+that change what a source returns, so a cached input is only reused for the same request. That includes the credentials
+sources are read with, like tokens and registry passwords, or those of the docker config file, since they decide what a
+source is allowed to return, which are hashed with the rest of the key rather than stored.
+*/
+func cacheFile(path string) string {
+	user, pass := registryUser, registryPass
+	// oci:// inputs fall back to the docker config file for the registry's credentials
+	if u, err := url.Parse(path); err == nil && u.Scheme == "oci" && user == "" {
+		user, pass = dockerConfigAuth(u.Host)
+	}
+	credentials := sha256.Sum256([]byte(strings.Join(append([]string{
+		firstNonEmpty(apiToken, os.Getenv("API_TOKEN")),
+		firstNonEmpty(githubToken, os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN")),
+		user, pass,
+	}, apiHeaders...), "\x00")))
+	key := strings.Join([]string{path, apiJSONPath, apiPaginate, apiParam, apiCursor, awsProfile, awsRegion,
+		hex.EncodeToString(credentials[:])}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:16]))
+}
+
+/*
+openCached returns the cached copy of the input at path if it is younger than cacheTTL. Otherwise the input is read
+from its source, and copied to a temporary file in the cache directory as it is read, so it is never held in memory as a
+whole. Cached inputs are only readable by the current user, since they may hold sensitive data.
+*/
+func openCached(ctx context.Context, path string) (io.ReadCloser, error) {
+	file := cacheFile(path)
+	if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) < cacheTTL {
+		l.Debug().Str("path", path).Str("cache", file).Msg("reading input from cache")
+		return openFile(file)
+	}
+
+	r, err := openSource(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cacheDir, 0o700); err != nil {
+		l.Warn().Err(err).Str("path", path).Msg("failed to create cache directory")
+		return r, nil
+	}
+	tmp, err := os.CreateTemp(cacheDir, "."+filepath.Base(file)+".*")
+	if err != nil {
+		l.Warn().Err(err).Str("path", path).Msg("failed to create cache file")
+		return r, nil
+	}
+	w := &cacheWriter{file: tmp}
+	return &cachingReader{Reader: io.TeeReader(r, w), source: r, cache: w, path: path, dest: file}, nil
+}
+
+/*
+cacheWriter writes to a temporary cache file. A failed write is recorded rather than returned, so an input isn't failed
+by a full disk, only left uncached.
+*/
+type cacheWriter struct {
+	file *os.File
+	err  error
+}
+
+// Write writes p to the cache file, unless an earlier write failed.
+func (w *cacheWriter) Write(p []byte) (int, error) {
+	if w.err == nil {
+		_, w.err = w.file.Write(p)
+	}
+	return len(p), nil
+}
+
+// cachingReader reads an input from its source, copying it to a temporary cache file as it is read.
+type cachingReader struct {
+	io.Reader
+	source io.ReadCloser
+	cache  *cacheWriter
+	path   string // path of the input
+	dest   string // path the cache file is renamed to once complete
+}
+
+/*
+Close reads the rest of the input, so one a parser read successfully but stopped reading early, like after the end of a
+JSON document, is still cached as a whole, and closes its source. The cache file is then renamed into place, which
+replaces an expired copy atomically, or removed if the input couldn't be read or cached in full. Inputs that failed to
+be read are aborted instead.
+*/
+func (c *cachingReader) Close() error {
+	_, readErr := io.Copy(io.Discard, c.Reader)
+	err := c.source.Close()
+	if closeErr := c.cache.file.Close(); c.cache.err == nil {
+		c.cache.err = closeErr
+	}
+	if readErr == nil && c.cache.err == nil {
+		c.cache.err = os.Rename(c.cache.file.Name(), c.dest)
+	}
+	if readErr != nil || c.cache.err != nil {
+		os.Remove(c.cache.file.Name())
+		if cacheErr := errors.Join(readErr, c.cache.err); !errors.Is(cacheErr, context.Canceled) {
+			l.Warn().Err(cacheErr).Str("path", c.path).Msg("failed to cache input")
+		}
+	}
+	return err
+}
+
+// Abort closes the source without reading any further and removes the cache file, leaving the input uncached.
+func (c *cachingReader) Abort() {
+	c.source.Close()
+	c.cache.file.Close()
+	os.Remove(c.cache.file.Name())
+}
+
+/*
+closeInput closes file, opened by openInput, once reading it finished with err. If err is set, the input failed to be
+read or reading was canceled, so an input being cached is aborted rather than downloaded to its end and cached.
+*/
+func closeInput(file io.ReadCloser, err error) {
+	if c, ok := file.(*cachingReader); ok && err != nil {
+		c.Abort()
+		return
+	}
+	file.Close()
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerParser("pem", "x509 certificates keyed by fingerprint, or subject and serial", (*fileSet).parsePEM)
+	registerDetector("pem", func(name string, _ *bufio.Reader) bool {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".pem", ".crt", ".cer":
+			return true
+		}
+		return false
+	}, func(head []byte) bool {
+		return bytes.HasPrefix(head, []byte("-----BEGIN "))
+	})
+}
+
+// tlsTimeout is how long to wait when connecting to a host to read its certificate.
+const tlsTimeout = 10 * time.Second
+
+/*
+openTLS returns the subject alternative names of a certificate, one per line. If target is an existing file, every
+certificate in it is read as PEM. Otherwise, target is a host with an optional port, defaulting to 443, and the leaf
+certificate it presents is read. The certificate is not verified, since its names are what is being audited.
+*/
+func openTLS(ctx context.Context, target string) (io.ReadCloser, error) {
+	var certs []*x509.Certificate
+	if _, err := os.Stat(target); err == nil {
+		if certs, err = readPEMCertificates(target); err != nil {
+			return nil, err
+		}
+	} else {
+		cert, err := fetchCertificate(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	var names []string
+	for _, cert := range certs {
+		names = append(names, subjectAltNames(cert)...)
+	}
+	l.Debug().Str("target", target).Int("names", len(names)).Msg("read certificate subject alternative names")
+	return linesReader(names), nil
+}
+
+// fetchCertificate connects to the host:port in address and returns the leaf certificate it presents.
+func fetchCertificate(ctx context.Context, address string) (*x509.Certificate, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+		address = net.JoinHostPort(address, "443")
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: tlsTimeout},
+		// #nosec G402 -- the certificate is inspected, not trusted
+		Config: &tls.Config{ServerName: host, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate presented by %s", address)
+	}
+	return certs[0], nil
+}
+
+// readPEMCertificates returns every certificate in the PEM file at path.
+func readPEMCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate file: %w", err)
+	}
+	certs, err := parsePEMCertificates(data, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return certs, nil
+}
+
+// parsePEMCertificates returns every certificate in the PEM encoded data read from name. Other PEM blocks are skipped.
+func parsePEMCertificates(data []byte, name string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate in %s: %w", name, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// subjectAltNames returns the DNS names, IP addresses, email addresses, and URIs in the certificate's SAN extension.
+func subjectAltNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	return names
+}
+
+/*
+parsePEM reads a PEM bundle from r and adds each certificate in it to the set, keyed according to certKey: either its
+SHA-256 fingerprint or its subject and serial number. The subject, serial, and expiry are kept as the element's columns.
+*/
+func (fs *fileSet) parsePEM(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	certs, err := parsePEMCertificates(data, fs.path)
+	if err != nil {
+		return err
+	}
+	for _, cert := range certs {
+		fs.addCertificate(cert)
+	}
+	return nil
+}
+
+/*
+parsePEMDir reads every file below the directory at fs.path as a PEM bundle and adds the certificates in them to the
+set, like a truststore directory. Files without any certificates are skipped.
+*/
+func (fs *fileSet) parsePEMDir(ctx context.Context) error {
+	return filepath.WalkDir(fs.path, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate file: %w", err)
+		}
+		certs, err := parsePEMCertificates(data, path)
+		if err != nil {
+			return err
+		}
+		l.Debug().Str("path", path).Int("certificates", len(certs)).Msg("read certificate file")
+		for _, cert := range certs {
+			fs.addCertificate(cert)
+		}
+		return nil
+	})
+}
+
+/*
+addCertificate adds cert to the set, keyed according to certKey. Keys identify a certificate exactly, so they are added
+without normalization, which would rewrite or drop them, like --numeric does with hex fingerprints.
+*/
+func (fs *fileSet) addCertificate(cert *x509.Certificate) {
+	subject := cert.Subject.String()
+	serial := cert.SerialNumber.Text(16)
+	columns := []string{subject, serial, cert.NotAfter.UTC().Format(time.RFC3339)}
+	if certKey == "subject-serial" {
+		fs.addExact(subject+" serial="+serial, columns)
+		return
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	fs.addExact(hex.EncodeToString(fingerprint[:]), columns)
+}
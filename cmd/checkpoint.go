@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// checkpoint is the persisted progress of reading an input, written to the checkpoint directory.
+type checkpoint struct {
+	Path      string                    `json:"path"`
+	Done      bool                      `json:"done"`
+	Line      int                       `json:"line"` // number of lines fully read, if not done
+	Set       []string                  `json:"set"`
+	Rows      map[string][]string       `json:"rows"`
+	Header    []string                  `json:"header"`
+	Column    int                       `json:"column,omitempty"` // field found in the header by --column
+	Spellings map[string]map[string]int `json:"spellings"`
+	First     map[string]string         `json:"first"`
+	Lines     map[string]int            `json:"lines"`
+	Order     map[string]int            `json:"order,omitempty"`
+	Raw       map[string]string         `json:"raw"`
+	Counts    map[string]int            `json:"counts"`
+	Sums      map[string]float64        `json:"sums"`
+}
+
+/*
+checkpointFile returns the path of the checkpoint for the input at path. It is named after a hash of the command line
+and path, so a checkpoint is only resumed by the same command, with the same flags affecting how elements are read.
+*/
+func checkpointFile(path string) string {
+	sum := sha256.Sum256([]byte(strings.Join(os.Args[1:], "\x00") + "\x00" + path))
+	return filepath.Join(checkpointDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+/*
+saveCheckpoint atomically writes the partial set of fs to its checkpoint file, along with the number of lines fully
+read, or marks it done once the input has been read completely.
+*/
+func (fs *fileSet) saveCheckpoint(done bool, line int) error {
+	cp := checkpoint{
+		Path:      fs.path,
+		Done:      done,
+		Line:      line,
+		Set:       fs.set.Elements(),
+		Rows:      fs.rows,
+		Header:    fs.header,
+		Column:    fs.column,
+		Spellings: fs.spellings,
+		First:     fs.first,
+		Lines:     fs.lines,
+		Order:     fs.order,
+		Raw:       fs.raw,
+		Counts:    fs.counts,
+		Sums:      fs.sums,
+	}
+	if err := os.MkdirAll(checkpointDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	path := checkpointFile(fs.path)
+	tmp, err := os.CreateTemp(checkpointDir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := json.NewEncoder(tmp).Encode(cp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	fs.saved = time.Now()
+	l.Debug().Str("path", fs.path).Bool("done", done).Int("line", line).Msg("saved checkpoint")
+	return nil
+}
+
+// loadCheckpoint reads the checkpoint of the input at path, returning nil if there is none.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(checkpointFile(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// restore replaces the partial set of fs with the one saved in cp.
+func (fs *fileSet) restore(cp *checkpoint) {
+	for _, element := range cp.Set {
+		fs.set.Insert(element, nil)
+	}
+	fs.header = cp.Header
+	fs.column = cp.Column
+	if cp.Lines != nil {
+		fs.lines = cp.Lines
+	}
+	if cp.Order != nil {
+		fs.order = cp.Order
+	}
+	if cp.Counts != nil {
+		fs.counts = cp.Counts
+	}
+	if cp.Rows != nil {
+		fs.rows = cp.Rows
+	}
+	if cp.Spellings != nil {
+		fs.spellings = cp.Spellings
+	}
+	if cp.First != nil {
+		fs.first = cp.First
+	}
+	if cp.Raw != nil {
+		fs.raw = cp.Raw
+	}
+	if cp.Sums != nil {
+		fs.sums = cp.Sums
+	}
+}
+
+/*
+checkpointedFileToSet reads the input like fileToSet, resuming from its checkpoint if a previous run was interrupted.
+A completely read input is restored without reading it again, and a partially read plain or CSV input is read from the
+first line that wasn't fully read. The input is checkpointed as done once it has been read.
+*/
+func (fs *fileSet) checkpointedFileToSet(ctx context.Context, parser string) error {
+	cp, err := loadCheckpoint(fs.path)
+	if err != nil {
+		return err
+	}
+	switch {
+	case cp == nil:
+		fs.saved = time.Now()
+		err = fs.fileToSet(ctx, parser)
+	case cp.Done:
+		l.Info().Str("path", fs.path).Msg("restored input from checkpoint")
+		fs.restore(cp)
+		return nil
+	default:
+		l.Info().Str("path", fs.path).Int("line", cp.Line).Msg("resuming input from checkpoint")
+		fs.restore(cp)
+		err = fs.resumeLines(ctx, cp.Line)
+	}
+	if err != nil {
+		return err
+	}
+	return fs.saveCheckpoint(true, 0)
+}
+
+// resumeLines skips the first n lines of the input and reads the rest with scanLines.
+func (fs *fileSet) resumeLines(ctx context.Context, n int) (err error) {
+	file, err := openInput(ctx, fs.path)
+	if err != nil {
+		return err
+	}
+	defer func() { closeInput(file, err) }()
+	br := bufio.NewReader(contextReader{ctx, file})
+	for fs.line < n {
+		if _, err := br.ReadString('\n'); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("input %s is shorter than its checkpoint, remove %s to start over", fs.path, checkpointFile(fs.path))
+			}
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		fs.line++
+	}
+	fs.saved = time.Now()
+	return fs.scanLines(br)
+}
+
+// removeCheckpoints removes the checkpoints of the inputs at paths once a run has completed.
+func removeCheckpoints(paths ...string) {
+	for _, path := range paths {
+		if err := os.Remove(checkpointFile(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			l.Warn().Err(err).Str("path", path).Msg("failed to remove checkpoint")
+		}
+	}
+}
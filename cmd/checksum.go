@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	registerParser("checksums", "sha256sum or md5sum style output as file names, keeping the checksum as a column", (*fileSet).parseChecksums)
+	registerDetector("checksums", func(name string, _ *bufio.Reader) bool {
+		return checksumFilePattern.MatchString(name)
+	}, nil)
+}
+
+var (
+	// checksumFilePattern matches the names of common checksum files, like SHA256SUMS or release.sha256.
+	checksumFilePattern = regexp.MustCompile(`(?i)^(?:(?:sha\d*|md5|b2)sums(?:\.txt)?|.*\.(?:sha\d+|md5))$`)
+	// bsdChecksumPattern matches the BSD style checksum format, e.g. "SHA256 (file.txt) = abc123".
+	bsdChecksumPattern = regexp.MustCompile(`^[A-Z0-9-]+ \((.+)\) = ([0-9A-Fa-f]+)$`)
+)
+
+/*
+parseChecksums reads checksum lists, as produced by sha256sum, md5sum, or their BSD style --tag output, from r and adds
+each file name to the set, keeping its checksum as the element's column. Use --rows to report files whose checksum
+changed. Blank lines and comments are skipped.
+*/
+func (fs *fileSet) parseChecksums(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		fs.line++
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := bsdChecksumPattern.FindStringSubmatch(line); m != nil {
+			fs.add(m[1], []string{strings.ToLower(m[2])})
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok {
+			l.Warn().Str("path", fs.path).Int("line", n).Msg("skipping invalid checksum line")
+			continue
+		}
+		// GNU tools separate the checksum and name with a space, followed by '*' for binary mode or another space
+		name = strings.TrimPrefix(strings.TrimPrefix(name, " "), "*")
+		fs.add(name, []string{strings.ToLower(sum)})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+)
+
+/*
+findColumn sets fs.column to the 1-based field of the header row line named by columnName, compared case
+insensitively, so the compared column is found by name however an export orders its columns. It returns an error if
+no field has that name.
+*/
+func (fs *fileSet) findColumn(line string) error {
+	fields := []string{line}
+	if delimiter != "" {
+		fields = diffit.SplitDelimited(line, delimiter)
+	}
+	for i, name := range fields {
+		if strings.EqualFold(strings.TrimSpace(name), columnName) {
+			fs.column = i + 1
+			return nil
+		}
+	}
+	return fmt.Errorf("column %s not found in header of %s", columnName, fs.path)
+}
+
+// selectedField returns the 1-based field compared as the element: the one found by findColumn, or the field flag.
+func (fs *fileSet) selectedField() int {
+	if fs.column > 0 {
+		return fs.column
+	}
+	return field
+}
+
+/*
+remainingIndex converts the 1-based field n of the rows of fs into an index into their remaining columns, which leave
+out the key, so fields before the key keep their position and those after it move up by one. It returns false if n is
+the key field.
+*/
+func (fs *fileSet) remainingIndex(n int) (int, bool) {
+	key := fs.selectedField()
+	switch {
+	case n == key:
+		return 0, false
+	case n < key:
+		return n - 1, true
+	}
+	return n - 2, true
+}
+
+// fieldNumber converts an index into the remaining columns of the rows of fs back into their 1-based field.
+func (fs *fileSet) fieldNumber(index int) int {
+	if index+1 < fs.selectedField() {
+		return index + 1
+	}
+	return index + 2
+}
+
+/*
+alignHeaderColumns rearranges the columns of fileB to the header order of fileA, followed by any columns only fileB
+has, so rows of files whose columns are in a different order are compared column by column by name.
+*/
+func (r *results) alignHeaderColumns() {
+	var header []string
+	seen := map[string]bool{}
+	for _, fs := range []*fileSet{&r.fileSetA, &r.fileSetB} {
+		for i, name := range fs.header {
+			// the key column is named by the first header, even if the other spells it differently
+			if (i == 0 && len(header) > 0) || seen[name] {
+				continue
+			}
+			seen[name] = true
+			header = append(header, name)
+		}
+	}
+	r.alignColumns(header)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `completion prints a completion script for the given shell, completing subcommands, flags, and the valid values of
+flags like --format, --input-format, and --parser. To load completions:
+
+  bash:       source <(goDiffIt completion bash)
+  zsh:        goDiffIt completion zsh > "${fpath[1]}/_goDiffIt"
+  fish:       goDiffIt completion fish > ~/.config/fish/completions/goDiffIt.fish
+  powershell: goDiffIt completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate completion script: %w", err)
+		}
+		return nil
+	},
+}
+
+/*
+registerCompletions registers the values completed for the enum flags of rootCmd. It is called once the flags are
+defined, and values that depend on registrations, like parser names, are looked up when completing.
+*/
+func registerCompletions() {
+	fixed := map[string][]string{
+		"format":            {"text", "csv", "json", "quickfix", "table", "markdown", "html", "diff"},
+		"input-format":      {"auto", "plain", "csv", "json", "yaml", "ansible"},
+		"api-paginate":      {"link", "page", "cursor"},
+		"cert-key":          {"fingerprint", "subject-serial"},
+		"case-output":       {"key", "first", "frequent"},
+		"fqdn-mode":         {"host", "subdomain", "domain"},
+		"outliers":          {"stddev", "iqr"},
+		"sort":              {"alpha", "numeric", "natural", "version", "none"},
+		"unicode-normalize": {"NFC", "NFKC"},
+	}
+	for name, values := range fixed {
+		mustRegister(name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+	}
+	mustRegister("parser", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		var values []string
+		for _, name := range parserNames() {
+			values = append(values, name+"\t"+parsers[name].description)
+		}
+		return values, cobra.ShellCompDirectiveNoFileComp
+	})
+	mustRegister("date-format", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		var values []string
+		for name := range dateLayouts {
+			values = append(values, name)
+		}
+		sort.Strings(values)
+		return values, cobra.ShellCompDirectiveNoFileComp
+	})
+	mustRegister("lang", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		var values []string
+		for _, tag := range languages {
+			values = append(values, tag.String())
+		}
+		return values, cobra.ShellCompDirectiveNoFileComp
+	})
+	for _, name := range []string{"cache-dir", "checkpoint"} {
+		if err := rootCmd.MarkFlagDirname(name); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// mustRegister registers f to complete the values of the rootCmd flag name, panicking if the flag isn't defined.
+func mustRegister(name string, f func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)) {
+	if err := rootCmd.RegisterFlagCompletionFunc(name, f); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
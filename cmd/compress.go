@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// compressionMagic maps the magic bytes that compressed streams start with to the name of their format.
+var compressionMagic = []struct {
+	name  string
+	magic []byte
+}{
+	{"gzip", []byte{0x1f, 0x8b}},
+	{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	// the block size digit is followed by the magic of the first block, or of the end of an empty stream
+	{"bzip2", []byte("BZh")},
+}
+
+/*
+decompressedReader is a decompressed input, closing the decompressor and the compressed input. format is the name of
+its compression format, or empty if the input wasn't compressed.
+*/
+type decompressedReader struct {
+	io.Reader
+	closers []io.Closer
+	format  string
+}
+
+// Close closes the decompressor and the compressed input, returning the first error.
+func (d *decompressedReader) Close() error {
+	var first error
+	for _, c := range d.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+/*
+decompress returns rc decompressed if it is a gzip, bzip2, xz, or zstd stream, detected by its magic bytes rather than
+its extension, so rotated log archives like syslog.2.gz are read like any other file. Other inputs are returned as is.
+*/
+func decompress(rc io.ReadCloser, path string) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	head, _ := br.Peek(10)
+	format := ""
+	for _, m := range compressionMagic {
+		if bytes.HasPrefix(head, m.magic) {
+			format = m.name
+			break
+		}
+	}
+	if format == "bzip2" && !isBzip2(head) {
+		format = ""
+	}
+	d := &decompressedReader{Reader: br, closers: []io.Closer{rc}, format: format}
+	switch format {
+	case "gzip":
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf("failed to read gzip input %s: %w", path, err)
+		}
+		d.Reader, d.closers = zr, append([]io.Closer{zr}, d.closers...)
+	case "bzip2":
+		d.Reader = bzip2.NewReader(br)
+	case "xz":
+		xr, err := xz.NewReader(br)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf("failed to read xz input %s: %w", path, err)
+		}
+		d.Reader = xr
+	case "zstd":
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf("failed to read zstd input %s: %w", path, err)
+		}
+		d.Reader, d.closers = zr, append([]io.Closer{zr.IOReadCloser()}, d.closers...)
+	default:
+		return d, nil
+	}
+	l.Debug().Str("path", path).Str("compression", format).Msg("decompressing input")
+	return d, nil
+}
+
+// isBzip2 reports whether head, starting with "BZh", continues like a bzip2 stream rather than text.
+func isBzip2(head []byte) bool {
+	if len(head) < 10 || head[3] < '1' || head[3] > '9' {
+		return false
+	}
+	block := head[4:10]
+	return bytes.Equal(block, []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) || bytes.Equal(block, []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90})
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptedExitCode is the exit code of a run interrupted by SIGINT or SIGTERM, as shells report for SIGINT.
+const interruptedExitCode = 130
+
+/*
+interruptContext returns a context canceled by SIGINT or SIGTERM, so an interrupted run can stop gracefully. Once it is
+canceled, the signals are restored to their default behavior, so interrupting again exits immediately.
+*/
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// contextReader is a reader that fails with the error of its context once it is canceled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read reads from the underlying reader unless the context is canceled.
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// contextWriter is a writer that fails with the error of its context once it is canceled.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+// Write writes to the underlying writer unless the context is canceled.
+func (c contextWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+/*
+exitIfInterrupted exits with interruptedExitCode if ctx was canceled, after reporting how much of each input in sets had
+been read, and whether that progress was checkpointed to be resumed. The report is logged as errors so it is shown
+without --verbose.
+*/
+func exitIfInterrupted(ctx context.Context, sets ...*fileSet) {
+	if ctx.Err() == nil {
+		return
+	}
+	l.Error().Msg("interrupted")
+	for _, fs := range sets {
+		l.Error().Str("path", fs.path).Int("lines", fs.line).Int("elements", fs.set.Len()).Msg("read before the interrupt")
+	}
+	if checkpointDir != "" && len(sets) > 0 {
+		l.Error().Str("checkpoint", checkpointDir).Msg("run again with the same arguments to resume from the checkpoint")
+	}
+	os.Exit(interruptedExitCode)
+}
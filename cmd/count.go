@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+/*
+setCounts returns the number of elements in each result set, as records with a set and count column, in output order.
+With --rows, the sets are removed, added, and changed, and pairs matched by --fuzzy are counted in the "A~B" set.
+*/
+func (r *results) setCounts() [][]string {
+	records := [][]string{{"set", "count"}}
+	if r.operation == "rows" {
+		return append(records,
+			[]string{"removed", strconv.Itoa(r.setAB.Size())},
+			[]string{"added", strconv.Itoa(r.setBA.Size())},
+			[]string{"changed", strconv.Itoa(len(r.changed))},
+		)
+	}
+	for _, rs := range r.resultSets() {
+		records = append(records, []string{rs.name, strconv.Itoa(len(rs.elements))})
+	}
+	if len(r.matches) > 0 {
+		records = append(records, []string{"A~B", strconv.Itoa(len(r.matches))})
+	}
+	return records
+}
+
+/*
+writeCounts writes the number of elements in each result set instead of the elements, in the format given by the format
+flag: "A-B: 3" lines as plain text, an object like {"A-B": 3, "B-A": 2} as JSON, or set and count columns otherwise.
+*/
+func (r *results) writeCounts(w io.Writer) error {
+	records := r.setCounts()
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(records); err != nil {
+			return fmt.Errorf("failed to write counts: %w", err)
+		}
+		return nil
+	case "table":
+		return writeTable(w, records)
+	case "markdown":
+		return writeMarkdown(w, records)
+	case "json":
+		counts := map[string]int{}
+		for _, record := range records[1:] {
+			counts[record[0]], _ = strconv.Atoi(record[1])
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(counts); err != nil {
+			return fmt.Errorf("failed to write counts: %w", err)
+		}
+		return nil
+	default:
+		for _, record := range records[1:] {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", record[0], record[1]); err != nil {
+				return fmt.Errorf("failed to write counts: %w", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	registerParser("crontab", "cron jobs, ignoring comments and environment assignments", (*fileSet).parseCrontab)
+	registerDetector("crontab", func(name string, _ *bufio.Reader) bool {
+		return strings.HasPrefix(name, "crontab")
+	}, nil)
+}
+
+// cronEnvPattern matches a crontab environment assignment, like "MAILTO=ops@example.com" or "PATH = /usr/bin".
+var cronEnvPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*=`)
+
+/*
+parseCrontab reads a crontab from r and adds each job to the set as its schedule and command, with runs of whitespace
+collapsed to a single space. Blank lines, comments, and environment assignments are skipped. If noSchedule is
+true, only the command is compared and the schedule is kept as the element's column.
+*/
+func (fs *fileSet) parseCrontab(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		fs.line++
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || cronEnvPattern.MatchString(line) {
+			continue
+		}
+		schedule, command, ok := splitCronEntry(line)
+		if !ok {
+			l.Warn().Str("path", fs.path).Int("line", n).Msg("skipping invalid crontab entry")
+			continue
+		}
+		if noSchedule {
+			fs.add(command, []string{schedule})
+			continue
+		}
+		fs.add(schedule+" "+command, []string{})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+/*
+splitCronEntry splits a crontab line into its schedule and command, each with whitespace collapsed. The schedule is
+either a nickname like @daily or the five time and date fields. It returns false if the line has no command.
+*/
+func splitCronEntry(line string) (schedule, command string, ok bool) {
+	fields := strings.Fields(line)
+	scheduleFields := 5
+	if strings.HasPrefix(fields[0], "@") {
+		scheduleFields = 1
+	}
+	if len(fields) <= scheduleFields {
+		return "", "", false
+	}
+	return strings.Join(fields[:scheduleFields], " "), strings.Join(fields[scheduleFields:], " "), true
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// curateHelp describes the answers accepted by the interactive prompt.
+const curateHelp = `y  accept this element
+n  reject this element
+Y  accept this and all remaining elements
+N  reject this and all remaining elements
++REGEX  accept this and all remaining elements matching REGEX
+-REGEX  reject this and all remaining elements matching REGEX
+q  quit without writing anything
+?  show this help`
+
+// curateCandidate is an element only present in one of the files, awaiting a decision.
+type curateCandidate struct {
+	element string
+	from    *fileSet
+}
+
+/*
+curate builds a merged list interactively. Elements present in both files are always kept, while each element only in
+one file is presented on prompt and accepted or rejected by reading answers from in, either one at a time or in bulk by
+pattern. The accepted lines, as first read from their file, are stored in r.curated. It returns an error if the session
+is quit or in ends before every element is decided.
+*/
+func (r *results) curate(in io.Reader, prompt io.Writer) error {
+	r.operation = "curate"
+	var candidates []curateCandidate
+	accepted := map[string]*fileSet{}
+	for _, element := range r.fileSetA.set.Elements() {
+		if r.fileSetB.set.Contains(element) {
+			accepted[element] = &r.fileSetA
+		} else {
+			candidates = append(candidates, curateCandidate{element, &r.fileSetA})
+		}
+	}
+	for _, element := range r.fileSetB.set.Elements() {
+		if !r.fileSetA.set.Contains(element) {
+			candidates = append(candidates, curateCandidate{element, &r.fileSetB})
+		}
+	}
+	fmt.Fprintf(prompt, "%d elements in both files are kept, %d to review. Type ? for help.\n", len(accepted), len(candidates))
+
+	decided := map[string]bool{}
+	scanner := bufio.NewScanner(in)
+	for i, c := range candidates {
+		if accept, ok := decided[c.element]; ok {
+			if accept {
+				accepted[c.element] = c.from
+			}
+			continue
+		}
+		for {
+			fmt.Fprintf(prompt, "[%d/%d] only in %s: %s\naccept? [y,n,Y,N,+re,-re,q,?] ", i+1, len(candidates), c.from.path, c.from.rawLine(c.element))
+			if !scanner.Scan() {
+				return fmt.Errorf("input ended before all elements were reviewed")
+			}
+			answer := strings.TrimSpace(scanner.Text())
+			switch answer {
+			case "q":
+				return fmt.Errorf("curation quit, nothing was written")
+			case "?", "":
+				fmt.Fprintln(prompt, curateHelp)
+				continue
+			}
+			n, accept, err := applyAnswer(answer, candidates[i:], decided)
+			if err != nil {
+				fmt.Fprintln(prompt, err)
+				continue
+			}
+			if n > 0 {
+				fmt.Fprintf(prompt, "%d elements decided\n", n)
+				var ok bool
+				if accept, ok = decided[c.element]; !ok {
+					continue
+				}
+			}
+			if accept {
+				accepted[c.element] = c.from
+			}
+			break
+		}
+	}
+
+	for _, element := range r.fileSetA.set.Elements() {
+		if from, ok := accepted[element]; ok {
+			r.curated = append(r.curated, from.rawLine(element))
+		}
+	}
+	for _, element := range r.fileSetB.set.Elements() {
+		if from, ok := accepted[element]; ok && from == &r.fileSetB {
+			r.curated = append(r.curated, from.rawLine(element))
+		}
+	}
+	return nil
+}
+
+/*
+applyAnswer applies a prompt answer to the remaining candidates, the first of which is being prompted for. Bulk answers
+record their decision for every matching candidate in decided and return how many matched, while y and n only return
+whether the current candidate is accepted. It returns an error for an unknown answer or invalid pattern.
+*/
+func applyAnswer(answer string, remaining []curateCandidate, decided map[string]bool) (int, bool, error) {
+	switch {
+	case answer == "y":
+		return 0, true, nil
+	case answer == "n":
+		return 0, false, nil
+	case answer == "Y", answer == "N":
+		for _, c := range remaining {
+			decided[c.element] = answer == "Y"
+		}
+		return len(remaining), answer == "Y", nil
+	case len(answer) > 1 && (answer[0] == '+' || answer[0] == '-'):
+		pattern, err := regexp.Compile(answer[1:])
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid pattern: %w", err)
+		}
+		n := 0
+		for _, c := range remaining {
+			if _, ok := decided[c.element]; !ok && pattern.MatchString(c.from.rawLine(c.element)) {
+				decided[c.element] = answer[0] == '+'
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, false, fmt.Errorf("no remaining elements match %s", answer[1:])
+		}
+		return n, false, nil
+	}
+	return 0, false, fmt.Errorf("unknown answer: %s, type ? for help", answer)
+}
+
+// printCurated writes the curated lines, one per line.
+func (r *results) printCurated(w io.Writer) error {
+	for _, line := range r.curated {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+	}
+	return nil
+}
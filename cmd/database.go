@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // registers the mysql driver
+	_ "github.com/lib/pq"              // registers the postgres driver
+	_ "modernc.org/sqlite"             // registers the sqlite driver
+)
+
+// databaseSchemes maps the scheme prefix of a database input to its database/sql driver.
+var databaseSchemes = map[string]string{
+	"postgres://":   "postgres",
+	"postgresql://": "postgres",
+	"mysql://":      "mysql",
+	"sqlite://":     "sqlite",
+}
+
+// databaseDriver returns the database/sql driver for the input at path, or an empty string if it isn't a database.
+func databaseDriver(path string) string {
+	for prefix, driver := range databaseSchemes {
+		if strings.HasPrefix(path, prefix) {
+			return driver
+		}
+	}
+	return ""
+}
+
+/*
+databaseDSN converts a database input into the data source name expected by its driver. postgres URLs are used as is,
+sqlite inputs are the path of the database file, opened read-only, and mysql URLs are converted to the driver's
+"user:password@tcp(host:port)/database?params" form.
+*/
+func databaseDSN(driver, path string) (string, error) {
+	switch driver {
+	case "sqlite":
+		file := strings.TrimPrefix(path, "sqlite://")
+		if _, err := os.Stat(file); err != nil {
+			return "", fmt.Errorf("file does not exist: %w", err)
+		}
+		// open read-only, so the database is never modified
+		return "file:" + file + "?mode=ro", nil
+	case "mysql":
+		u, err := url.Parse(path)
+		if err != nil {
+			return "", fmt.Errorf("invalid mysql input: %w", err)
+		}
+		host := u.Host
+		if u.Port() == "" {
+			host += ":3306"
+		}
+		return fmt.Sprintf("%s@tcp(%s)%s?%s", u.User.String(), host, u.Path, u.RawQuery), nil
+	}
+	return path, nil
+}
+
+/*
+queryToSet runs fs.query against the database at fs.path and adds each row to the set, streaming them as they are read.
+The first column of each row is used as the element and the remaining columns are kept as its columns, while the column
+names are stored as the header, so rows can be compared by key, or by key and values with --rows.
+*/
+func (fs *fileSet) queryToSet(ctx context.Context, driver string) error {
+	if fs.query == "" {
+		return fmt.Errorf("%s is a database, a query must be set with --query", fs.path)
+	}
+	dsn, err := databaseDSN(driver, fs.path)
+	if err != nil {
+		return err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, fs.query)
+	if err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
+	defer rows.Close()
+	if fs.header, err = rows.Columns(); err != nil {
+		return fmt.Errorf("failed to read query columns: %w", err)
+	}
+	values := make([]any, len(fs.header))
+	pointers := make([]any, len(values))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("failed to read query row: %w", err)
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = sqlString(v)
+		}
+		fs.add(row[0], row[1:])
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read query rows: %w", err)
+	}
+	l.Debug().Str("driver", driver).Int("rows", count).Msg("read query results")
+	return nil
+}
+
+// sqlString returns the string form of a value scanned from a database row. NULL is returned as an empty string.
+func sqlString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
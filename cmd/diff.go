@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+/*
+writeDiff writes the results as a single sorted stream of unified diff lines, so they can be reviewed with existing diff
+highlighting tools: elements only in fileA are prefixed with "-", elements only in fileB with "+", and elements in both,
+like those of an intersection or union, with a space. A changed row is written as its fileA version followed by its
+fileB version. Unless the pipe flag is set, "---" and "+++" lines naming the files come first.
+*/
+func (r *results) writeDiff(w io.Writer) error {
+	if !pipe {
+		if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", r.fileSetA.path, r.fileSetB.path); err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+	}
+	all := hashset.New()
+	prefixes := map[string]string{}
+	mark := func(element, prefix string) {
+		all.Add(element)
+		prefixes[element] += prefix
+	}
+	switch r.operation {
+	case "difference", "subset", "rows":
+		for _, element := range r.setAB.Values() {
+			mark(element.(string), "-")
+		}
+		for _, element := range r.setBA.Values() {
+			mark(element.(string), "+")
+		}
+		for _, c := range r.changed {
+			mark(c.key, "-+")
+		}
+	case "superset":
+		for _, element := range r.setAB.Values() {
+			mark(element.(string), "+")
+		}
+	case "intersection", "union":
+		for _, v := range r.setAB.Values() {
+			element := v.(string)
+			switch {
+			case !r.fileSetB.set.Contains(element):
+				mark(element, "-")
+			case !r.fileSetA.set.Contains(element):
+				mark(element, "+")
+			default:
+				mark(element, " ")
+			}
+		}
+	}
+
+	for _, element := range sortElements(*all, &r.fileSetA, &r.fileSetB) {
+		for _, prefix := range prefixes[element] {
+			line := r.textLine(element, &r.fileSetA, &r.fileSetB)
+			if prefix == '+' {
+				line = r.textLine(element, &r.fileSetB, &r.fileSetA)
+			}
+			if _, err := fmt.Fprintf(w, "%c%s\n", prefix, line); err != nil {
+				return fmt.Errorf("failed to write diff: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// domainStats are the number of elements of a domain suffix only in each file and in both, for --group-by-domain.
+type domainStats struct {
+	Domain string `json:"domain"`
+	OnlyA  int    `json:"onlyA"`
+	OnlyB  int    `json:"onlyB"`
+	Both   int    `json:"both"`
+}
+
+/*
+domainOf returns the domain suffix of the host name name, everything after its first dot, lowercased, e.g. corp.net for
+web01.corp.net. It is empty for names without a dot and for IP addresses. A trailing dot is ignored.
+*/
+func domainOf(name string) string {
+	name = strings.TrimSuffix(name, ".")
+	if net.ParseIP(name) != nil {
+		return ""
+	}
+	_, domain, _ := strings.Cut(name, ".")
+	return strings.ToLower(domain)
+}
+
+/*
+domain returns the domain suffix of element: the one recorded when it was read, since --ignore-fqdn removes it from the
+element, or else that of the element itself.
+*/
+func (fs *fileSet) domain(element string) string {
+	if domain, ok := fs.domains[element]; ok {
+		return domain
+	}
+	return domainOf(element)
+}
+
+/*
+domainStats breaks the stats of the results down by domain suffix, so the environment drift comes from stands out.
+Domains are sorted by the number of elements only in either file, most first, then by the number in both and by name.
+An element in both files is counted under its domain in fileA.
+*/
+func (r *results) domainStats() []domainStats {
+	byDomain := map[string]*domainStats{}
+	count := func(domain string) *domainStats {
+		if _, ok := byDomain[domain]; !ok {
+			byDomain[domain] = &domainStats{Domain: domain}
+		}
+		return byDomain[domain]
+	}
+	for _, element := range r.fileSetA.set.Keys() {
+		if r.fileSetB.set.Contains(element) {
+			count(r.fileSetA.domain(element)).Both++
+		} else {
+			count(r.fileSetA.domain(element)).OnlyA++
+		}
+	}
+	for _, element := range r.fileSetB.set.Keys() {
+		if !r.fileSetA.set.Contains(element) {
+			count(r.fileSetB.domain(element)).OnlyB++
+		}
+	}
+	stats := make([]domainStats, 0, len(byDomain))
+	for _, s := range byDomain {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.OnlyA+a.OnlyB != b.OnlyA+b.OnlyB {
+			return a.OnlyA+a.OnlyB > b.OnlyA+b.OnlyB
+		}
+		if a.Both != b.Both {
+			return a.Both > b.Both
+		}
+		return a.Domain < b.Domain
+	})
+	return stats
+}
+
+// domainRecords returns the domain stats as a table with a header row. Names without a domain are listed as "-".
+func (r *results) domainRecords() [][]string {
+	records := [][]string{{
+		translate("Domain"), translate("Only in %s", r.fileSetA.path), translate("Only in %s", r.fileSetB.path),
+		translate("In both"),
+	}}
+	for _, s := range r.domainStats() {
+		domain := s.Domain
+		if domain == "" {
+			domain = "-"
+		}
+		records = append(records, []string{domain, strconv.Itoa(s.OnlyA), strconv.Itoa(s.OnlyB), strconv.Itoa(s.Both)})
+	}
+	return records
+}
+
+// domainMetrics returns the domain stats as metric,value records, like domains[corp.net].onlyA.
+func (r *results) domainMetrics() [][]string {
+	var records [][]string
+	for _, s := range r.domainStats() {
+		prefix := fmt.Sprintf("domains[%s].", s.Domain)
+		records = append(records,
+			[]string{prefix + "onlyA", strconv.Itoa(s.OnlyA)},
+			[]string{prefix + "onlyB", strconv.Itoa(s.OnlyB)},
+			[]string{prefix + "both", strconv.Itoa(s.Both)},
+		)
+	}
+	return records
+}
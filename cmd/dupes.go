@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes [file]",
+	Short: "Report the elements of a file that appear more than once",
+	Long: `dupes reads a single file and reports the elements that appear more than once after normalization, with the
+number of times each appears, most frequent first. Comparisons collapse duplicates into one element, so this shows what
+they discard, like hosts listed twice in an inventory or the same user with different capitalization.
+
+Elements are normalized the same way they are compared, so pass the normalization flags used for comparisons, like
+--ignore-fqdn, --replace, or --case-sensitive. Results are printed as "element,count" lines, or with --format as CSV
+with a value and count column, or as a JSON array of objects with a value and count.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch format {
+		case "text", "csv", "json":
+		default:
+			return fmt.Errorf("invalid format: %s", format)
+		}
+		if err := prepareNormalization(cmd); err != nil {
+			return err
+		}
+		// duplicates are counted the same way as by --multiset
+		multiset = true
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := reportDupes(cmd.Context(), args[0], os.Stdout); err != nil {
+			l.Fatal().Err(err).Send()
+		}
+	},
+}
+
+/*
+reportDupes reads the input at path and writes each element seen more than once to w, with the number of times it was
+seen, in the format selected by the format flag. Elements are ordered by count, most frequent first, then by element.
+*/
+func reportDupes(ctx context.Context, path string, w io.Writer) error {
+	fs := newFileSet(path)
+	if err := fs.fileToSet(ctx, parserName); err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	var dupes []jsonElement
+	for element, count := range fs.counts {
+		if count > 1 {
+			dupes = append(dupes, jsonElement{Value: element, Count: count})
+		}
+	}
+	sort.Slice(dupes, func(i, j int) bool {
+		if dupes[i].Count != dupes[j].Count {
+			return dupes[i].Count > dupes[j].Count
+		}
+		return dupes[i].Value < dupes[j].Value
+	})
+
+	switch format {
+	case "csv":
+		records := [][]string{{"value", "count"}}
+		for _, d := range dupes {
+			records = append(records, []string{d.Value, strconv.Itoa(d.Count)})
+		}
+		if err := csv.NewWriter(w).WriteAll(records); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if dupes == nil {
+			dupes = []jsonElement{}
+		}
+		if err := enc.Encode(dupes); err != nil {
+			return fmt.Errorf("failed to write json: %w", err)
+		}
+	default:
+		for _, d := range dupes {
+			if _, err := fmt.Fprintf(w, "%s%s%d\n", d.Value, delimiter, d.Count); err != nil {
+				return fmt.Errorf("failed to write results: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(dupesCmd)
+	dupesCmd.Flags().StringVar(&format, "format", "text", "output format: text, csv, or json")
+	dupesCmd.Flags().StringVar(&parserName, "parser", "", "parser for the input, detected from its name and content by default")
+	// the flags that affect how elements are normalized, so duplicates match the way elements are compared
+	addNormalizationFlags(dupesCmd)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+)
+
+/*
+outputFile is a destination for results, closing any encryption and the file it writes to in order. Files are written
+to a temporary file next to path, which only replaces path once it is closed, so an interrupted run never leaves a
+truncated output behind. The file is then signed if signingKey is set.
+*/
+type outputFile struct {
+	io.Writer
+	closers []io.Closer
+	path    string
+	tmp     string
+}
+
+// Close finishes the output, moving it into place and signing it, returning the first error.
+func (o *outputFile) Close() error {
+	var first error
+	for _, c := range o.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	if o.tmp == "" {
+		return first
+	}
+	if first != nil {
+		os.Remove(o.tmp)
+		return first
+	}
+	if err := os.Rename(o.tmp, o.path); err != nil {
+		os.Remove(o.tmp)
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if signingKey != nil {
+		return signFile(o.path)
+	}
+	return nil
+}
+
+/*
+Abort discards the output, stopping gpg if it encrypts it, and closing the file it writes to and removing it without
+replacing path.
+*/
+func (o *outputFile) Abort() {
+	// closing the encryption would write its trailer, so gpg is killed instead, and only the file itself is closed
+	for _, c := range o.closers {
+		if g, ok := c.(*gpgCommand); ok {
+			g.kill()
+		}
+	}
+	if o.tmp == "" {
+		return
+	}
+	o.closers[len(o.closers)-1].Close()
+	os.Remove(o.tmp)
+}
+
+/*
+createOutput creates the file at path for writing results, or writes to stdout if path is empty or "-". If encryptTo is
+set, everything written is encrypted to its recipients, ASCII-armored when written to stdout. Writes fail once ctx is
+canceled. The output must be closed to finish the encryption, replace path, and sign it if signingKey is set, or aborted
+to discard it.
+*/
+func createOutput(ctx context.Context, path string) (*outputFile, error) {
+	out := &outputFile{Writer: os.Stdout}
+	if path != "" && path != "-" {
+		f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		// temporary files are private, so keep the mode of the file being replaced, or the usual mode of a new one
+		mode := os.FileMode(0o644)
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := f.Chmod(mode); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		out.Writer = f
+		out.closers = append(out.closers, f)
+		out.path, out.tmp = path, f.Name()
+	}
+	if len(encryptTo) > 0 {
+		enc, err := encryptWriter(out.Writer, out.Writer == os.Stdout)
+		if err != nil {
+			out.Abort()
+			return nil, err
+		}
+		out.Writer = enc
+		out.closers = append([]io.Closer{enc}, out.closers...)
+	}
+	out.Writer = contextWriter{ctx, out.Writer}
+	return out, nil
+}
+
+/*
+encryptWriter returns a writer encrypting to the recipients in encryptTo and writing the ciphertext to w. Recipients
+that are age public keys (age1...) or SSH public keys are encrypted to with age, anything else is treated as a GPG key
+ID or email address and encrypted to with the gpg command, which must be installed with the recipients' keys imported.
+It returns an error if age and GPG recipients are mixed or a recipient is invalid.
+*/
+func encryptWriter(w io.Writer, armored bool) (io.WriteCloser, error) {
+	var recipients []age.Recipient
+	for _, r := range encryptTo {
+		switch {
+		case strings.HasPrefix(r, "age1"):
+			recipient, err := age.ParseX25519Recipient(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid age recipient: %w", err)
+			}
+			recipients = append(recipients, recipient)
+		case strings.HasPrefix(r, "ssh-"):
+			recipient, err := agessh.ParseRecipient(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ssh recipient: %w", err)
+			}
+			recipients = append(recipients, recipient)
+		}
+	}
+	if len(recipients) == 0 {
+		return gpgWriter(w, armored)
+	}
+	if len(recipients) != len(encryptTo) {
+		return nil, fmt.Errorf("age and gpg recipients can't be mixed")
+	}
+	if !armored {
+		enc, err := age.Encrypt(w, recipients...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt output: %w", err)
+		}
+		return enc, nil
+	}
+	aw := armor.NewWriter(w)
+	enc, err := age.Encrypt(aw, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	return &outputFile{Writer: enc, closers: []io.Closer{enc, aw}}, nil
+}
+
+// gpgCommand runs gpg, encrypting its stdin to the recipients in encryptTo.
+type gpgCommand struct {
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+// Close closes the input of gpg and waits for it to finish.
+func (g *gpgCommand) Close() error {
+	if err := g.WriteCloser.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if err := g.cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to encrypt output with gpg: %w", err)
+	}
+	return nil
+}
+
+// kill stops gpg without letting it finish the encryption, and waits for it to exit.
+func (g *gpgCommand) kill() {
+	g.cmd.Process.Kill()
+	g.WriteCloser.Close()
+	g.cmd.Wait()
+}
+
+// gpgWriter returns a writer encrypting to the recipients in encryptTo with gpg, writing the ciphertext to w.
+func gpgWriter(w io.Writer, armored bool) (io.WriteCloser, error) {
+	args := []string{"--batch", "--yes", "--encrypt"}
+	if armored {
+		args = append(args, "--armor")
+	}
+	for _, r := range encryptTo {
+		args = append(args, "--recipient", r)
+	}
+	cmd := exec.Command("gpg", args...)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run gpg: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run gpg: %w", err)
+	}
+	return &gpgCommand{WriteCloser: stdin, cmd: cmd}, nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+/*
+expandGlobs returns the files matched by patterns, in order, each pattern's matches sorted by name. Patterns without
+glob characters, stdin, and sources like https:// are returned as they are, so they fail as usual when read. Returns an
+error if a pattern is malformed or matches no files.
+*/
+func expandGlobs(patterns []string) ([]string, error) {
+	var paths []string
+	for _, pattern := range patterns {
+		if pattern == stdinPath || strings.Contains(pattern, "://") || !strings.ContainsAny(pattern, `*?[\`) {
+			paths = append(paths, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", pattern)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+/*
+filesToSet reads every file matched by the patterns in fs.paths into the set, as if they were concatenated. Each file
+is read like a single input, so its own header row and leading lines are skipped, and the header of the first file is
+kept. fs.path is restored afterwards, since it names the whole side in the output.
+*/
+func (fs *fileSet) filesToSet(ctx context.Context, parser string) error {
+	paths, err := expandGlobs(fs.paths)
+	if err != nil {
+		return err
+	}
+	name, patterns := fs.path, fs.paths
+	defer func() { fs.path, fs.paths = name, patterns }()
+	fs.paths = nil
+
+	var header []string
+	for _, path := range paths {
+		l.Debug().Str("side", name).Str("path", path).Msg("reading file")
+		fs.path, fs.line, fs.header, fs.column = path, 0, nil, 0
+		if err := fs.fileToSet(ctx, parser); err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if header == nil {
+			header = fs.header
+		}
+	}
+	fs.header = header
+	return nil
+}
+
+// sideName names an input read from several files or globs in the output, by joining its patterns.
+func sideName(patterns []string) string {
+	return strings.Join(patterns, ", ")
+}
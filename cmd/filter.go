@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numericBounds is an inclusive range of numbers. Unset bounds are infinite.
+type numericBounds struct {
+	min float64
+	max float64
+}
+
+// numericRangeBounds holds the parsed numeric-range flag.
+var numericRangeBounds = numericBounds{min: math.Inf(-1), max: math.Inf(1)}
+
+/*
+parseNumericRange parses a range in the form MIN:MAX. Either bound may be omitted to leave that side open, e.g. "100:"
+includes every value of 100 or more. It returns an error if a bound is not a number or MIN is greater than MAX.
+*/
+func parseNumericRange(s string) (numericBounds, error) {
+	b := numericBounds{min: math.Inf(-1), max: math.Inf(1)}
+	lo, hi, ok := strings.Cut(s, ":")
+	if !ok {
+		return b, fmt.Errorf("invalid numeric range, expected MIN:MAX: %s", s)
+	}
+	var err error
+	if lo = strings.TrimSpace(lo); lo != "" {
+		if b.min, err = strconv.ParseFloat(lo, 64); err != nil {
+			return b, fmt.Errorf("invalid numeric range minimum: %w", err)
+		}
+	}
+	if hi = strings.TrimSpace(hi); hi != "" {
+		if b.max, err = strconv.ParseFloat(hi, 64); err != nil {
+			return b, fmt.Errorf("invalid numeric range maximum: %w", err)
+		}
+	}
+	if b.min > b.max {
+		return b, fmt.Errorf("numeric range minimum %v is greater than maximum %v", b.min, b.max)
+	}
+	return b, nil
+}
+
+// contains reports whether n is within the bounds.
+func (b numericBounds) contains(n float64) bool {
+	return n >= b.min && n <= b.max
+}
+
+// dateBounds is an inclusive range of times. Zero bounds are open.
+type dateBounds struct {
+	start time.Time
+	end   time.Time
+}
+
+// dateRangeBounds holds the parsed date-range flag.
+var dateRangeBounds dateBounds
+
+// dateLayouts maps the named layouts accepted by the date-format flag to their Go reference layouts.
+var dateLayouts = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"date":     time.DateOnly,
+	"datetime": time.DateTime,
+	"rfc1123":  time.RFC1123,
+	"unix":     "unix",
+}
+
+// dateLayout returns the Go reference layout for the date-format flag, which is either a named layout or a layout itself.
+func dateLayout() string {
+	if layout, ok := dateLayouts[strings.ToLower(dateFormat)]; ok {
+		return layout
+	}
+	return dateFormat
+}
+
+/*
+parseDate parses s using layout. The special layout "unix" parses seconds since the epoch. Values that don't match layout
+are also tried as RFC 3339 timestamps and plain dates, which allows date-range bounds to be written in ISO form.
+*/
+func parseDate(s, layout string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var err error
+	if layout == "unix" {
+		sec, perr := strconv.ParseInt(s, 10, 64)
+		if perr == nil {
+			return time.Unix(sec, 0).UTC(), nil
+		}
+		err = fmt.Errorf("invalid unix timestamp: %w", perr)
+	} else {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	for _, fallback := range []string{time.RFC3339, time.DateOnly} {
+		if t, ferr := time.Parse(fallback, s); ferr == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+/*
+parseDateRange parses a range in the form START..END, where each bound is parsed with the date-format layout. Either
+bound may be omitted to leave that side open. It returns an error if a bound is not a date or START is after END.
+*/
+func parseDateRange(s string) (dateBounds, error) {
+	var b dateBounds
+	start, end, ok := strings.Cut(s, "..")
+	if !ok {
+		return b, fmt.Errorf("invalid date range, expected START..END: %s", s)
+	}
+	var err error
+	if start = strings.TrimSpace(start); start != "" {
+		if b.start, err = parseDate(start, dateLayout()); err != nil {
+			return b, fmt.Errorf("invalid date range start: %w", err)
+		}
+	}
+	if end = strings.TrimSpace(end); end != "" {
+		if b.end, err = parseDate(end, dateLayout()); err != nil {
+			return b, fmt.Errorf("invalid date range end: %w", err)
+		}
+	}
+	if !b.start.IsZero() && !b.end.IsZero() && b.start.After(b.end) {
+		return b, fmt.Errorf("date range start %s is after end %s", start, end)
+	}
+	return b, nil
+}
+
+// contains reports whether t is within the bounds.
+func (b dateBounds) contains(t time.Time) bool {
+	return (b.start.IsZero() || !t.Before(b.start)) && (b.end.IsZero() || !t.After(b.end))
+}
+
+/*
+inDateRange reports whether the row of fs made up of element and columns falls within the date-range bounds. The date is
+read from the 1-based dateField, numbered by its position in the row like the field flag, so it is the element itself if
+it is the selected field. Rows without a parsable date are excluded.
+*/
+func (fs *fileSet) inDateRange(element string, columns []string) bool {
+	value := element
+	if i, ok := fs.remainingIndex(dateField); ok {
+		if i >= len(columns) {
+			l.Debug().Str("element", element).Int("field", dateField).Msg("skipping row without a date field")
+			return false
+		}
+		value = columns[i]
+	}
+	t, err := parseDate(value, dateLayout())
+	if err != nil {
+		l.Debug().Str("element", element).Str("value", value).Err(err).Msg("skipping row with an unparsable date")
+		return false
+	}
+	return dateRangeBounds.contains(t)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func init() {
+	registerParser("iptables", "iptables-save rules, ignoring counters and match option order", (*fileSet).parseIptables)
+	registerParser("nft", "nft list ruleset rules, ignoring counters and handles", (*fileSet).parseNft)
+	registerDetector("iptables", nil, func(head []byte) bool {
+		for _, prefix := range []string{
+			"# Generated by iptables-save", "# Generated by ip6tables-save", "*filter", "*nat", "*mangle", "*raw",
+		} {
+			if bytes.HasPrefix(head, []byte(prefix)) {
+				return true
+			}
+		}
+		return false
+	})
+	registerDetector("nft", nil, func(head []byte) bool {
+		return bytes.HasPrefix(head, []byte("table "))
+	})
+}
+
+var (
+	// iptablesCounterPattern matches the packet and byte counters iptables-save -c prints before rules and policies.
+	iptablesCounterPattern = regexp.MustCompile(`\s*\[\d+:\d+\]\s*`)
+	// nftCounterPattern matches the packet and byte values of an nft counter statement.
+	nftCounterPattern = regexp.MustCompile(`\bcounter packets \d+ bytes \d+`)
+	// nftHandlePattern matches the rule handle comment printed by nft -a.
+	nftHandlePattern = regexp.MustCompile(`\s*# handle \d+\s*$`)
+)
+
+/*
+parseIptables reads iptables-save (or ip6tables-save) output from r and adds each rule and chain policy to the set as
+"table/chain: rule". Counters are removed and the match options of each rule are sorted, so rules that only differ by
+option order or hit counts are equal. The target and its options stay last, in their original order.
+*/
+func (fs *fileSet) parseIptables(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	table := ""
+	for scanner.Scan() {
+		fs.line++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), line == "COMMIT":
+			continue
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+			continue
+		case strings.HasPrefix(line, ":"):
+			// chain policy, e.g. ":INPUT DROP [0:0]"
+			fields := strings.Fields(iptablesCounterPattern.ReplaceAllString(line[1:], " "))
+			if len(fields) >= 2 {
+				fs.addExact(fmt.Sprintf("%s/%s: policy %s", table, fields[0], fields[1]), []string{})
+			}
+			continue
+		}
+		line = strings.TrimSpace(iptablesCounterPattern.ReplaceAllString(line, " "))
+		fields := splitQuoted(line)
+		if len(fields) < 2 || (fields[0] != "-A" && fields[0] != "--append") {
+			l.Debug().Str("path", fs.path).Str("line", line).Msg("skipping unrecognized iptables line")
+			continue
+		}
+		fs.addExact(fmt.Sprintf("%s/%s: %s", table, fields[1], normalizeIptablesRule(fields[2:])), []string{})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+/*
+normalizeIptablesRule groups the fields of a rule into options, each made up of a flag, an optional preceding "!", and
+its arguments. The match options are sorted, while the target (-j or -g) and everything after it keep their order.
+*/
+func normalizeIptablesRule(fields []string) string {
+	var options []string
+	var current []string
+	target := -1
+	for i, field := range fields {
+		if field == "-j" || field == "--jump" || field == "-g" || field == "--goto" {
+			target = i
+			break
+		}
+		startsOption := strings.HasPrefix(field, "-") && len(field) > 1
+		negatesNext := field == "!"
+		if (startsOption || negatesNext) && !(len(current) == 1 && current[0] == "!") {
+			if len(current) > 0 {
+				options = append(options, strings.Join(current, " "))
+			}
+			current = nil
+		}
+		current = append(current, field)
+	}
+	if len(current) > 0 {
+		options = append(options, strings.Join(current, " "))
+	}
+	sort.Strings(options)
+	if target >= 0 {
+		options = append(options, strings.Join(fields[target:], " "))
+	}
+	return strings.Join(options, " ")
+}
+
+/*
+parseNft reads nft list ruleset output from r and adds each rule, along with each chain's type and policy statement, to
+the set as "family table/chain: rule". Counter values and rule handles are removed, so rules that only differ by hit
+counts are equal. Sets, maps, and other non-chain blocks are skipped.
+*/
+func (fs *fileSet) parseNft(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	var blocks []string // the kind of each open block, e.g. "table" or "chain"
+	table, chain := "", ""
+	for scanner.Scan() {
+		fs.line++
+		line := strings.Join(strings.Fields(scanner.Text()), " ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		opens, closes := strings.Count(line, "{"), strings.Count(line, "}")
+		switch {
+		case opens > closes:
+			fields := strings.Fields(line)
+			switch {
+			case fields[0] == "table" && len(fields) >= 4:
+				table = fields[1] + " " + fields[2]
+			case fields[0] == "table" && len(fields) == 3:
+				// the family defaults to ip when omitted
+				table = "ip " + fields[1]
+			case fields[0] == "chain" && len(fields) >= 3:
+				chain = fields[1]
+			}
+			blocks = append(blocks, fields[0])
+			continue
+		case closes > opens:
+			for i := 0; i < closes-opens && len(blocks) > 0; i++ {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+		if len(blocks) == 0 || blocks[len(blocks)-1] != "chain" {
+			continue
+		}
+		line = nftHandlePattern.ReplaceAllString(line, "")
+		line = nftCounterPattern.ReplaceAllString(line, "counter")
+		fs.addExact(fmt.Sprintf("%s/%s: %s", table, chain, line), []string{})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
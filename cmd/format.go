@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	registerParser("plain", "one element per line, the first field if the line contains the delimiter", (*fileSet).scanLines)
+	registerParser("csv", "delimited rows keyed on the first column, with RFC 4180 quoted fields", (*fileSet).scanLines)
+	registerParser("json", "an array of values, or of objects keyed by --key-field, or the values selected by --json-path", (*fileSet).parseJSON)
+	registerParser("yaml", "the entries of lists, or keys of maps, in one or more documents, or objects keyed by --key-field", (*fileSet).parseYAML)
+}
+
+// sniffLength is the number of bytes peeked at when detecting the format of an input from its content.
+const sniffLength = 512
+
+/*
+detectFormat returns the name of the parser for the file at path. Parsers registered with a detector for the base name
+of path, like authorized_keys, crontab, hosts, or SHA256SUMS, are tried first, then the extensions of the generic
+formats, like *.json. Otherwise the format is guessed from the first bytes buffered in r: parsers registered with a
+detector for the content, like PEM or syslog, are tried first, then a leading '[' or '{' is JSON if it decodes as such
+and plain text otherwise, a leading YAML document marker or list item is YAML, a line containing the delimiter is CSV,
+and anything else is plain text. Detectors are tried in the order of the parsers' names. The extension of a compressed
+input is ignored. Names are ignored for inputs read from a source, like tls://, since their content doesn't match the
+name.
+*/
+func detectFormat(path string, r *bufio.Reader) string {
+	if !strings.Contains(path, "://") {
+		// compressed inputs are detected by the name they had before compression, e.g. data.csv.gz as data.csv
+		for _, ext := range []string{".gz", ".bz2", ".xz", ".zst"} {
+			path = strings.TrimSuffix(path, ext)
+		}
+		for _, name := range parserNames() {
+			if match := parsers[name].byName; match != nil && match(filepath.Base(path), r) {
+				return name
+			}
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			return "json"
+		case ".yaml", ".yml":
+			return "yaml"
+		case ".csv", ".tsv":
+			return "csv"
+		case ".txt", ".list":
+			return "plain"
+		}
+	}
+
+	head, _ := r.Peek(sniffLength)
+	trimmed := bytes.TrimSpace(head)
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	for _, name := range parserNames() {
+		if match := parsers[name].byContent; match != nil && match(trimmed) {
+			return name
+		}
+	}
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{")):
+		if looksLikeJSON(head, len(head) < sniffLength) {
+			return "json"
+		}
+		return "plain"
+	case bytes.HasPrefix(trimmed, []byte("---")), bytes.HasPrefix(trimmed, []byte("- ")):
+		return "yaml"
+	}
+	if delimiter != "" && bytes.Contains(firstLine, []byte(delimiter)) {
+		return "csv"
+	}
+	return "plain"
+}
+
+/*
+looksLikeJSON reports whether head decodes as JSON, so INI sections like [prod] aren't mistaken for an array. If
+complete is false, head is only the start of the input, and it is enough for it to be valid up to where it is cut off.
+*/
+func looksLikeJSON(head []byte, complete bool) bool {
+	if complete {
+		return json.Valid(head)
+	}
+	dec := json.NewDecoder(bytes.NewReader(head))
+	for {
+		if _, err := dec.Token(); err != nil {
+			var syntaxErr *json.SyntaxError
+			return !errors.As(err, &syntaxErr)
+		}
+	}
+}
+
+/*
+parseJSON decodes a JSON array from r and adds each of its values to the set with addValues. If jsonPath is set, the
+document can be any JSON value, and the values it selects, e.g. with "items[].hostname", are added instead.
+*/
+func (fs *fileSet) parseJSON(r io.Reader) error {
+	var values []any
+	if jsonPath != "" {
+		doc, err := decodeJSON(r)
+		if err != nil {
+			return fmt.Errorf("failed to decode json: %w", err)
+		}
+		if values, err = evalJSONPath(doc, jsonPath); err != nil {
+			return err
+		}
+	} else {
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		if err := dec.Decode(&values); err != nil {
+			return fmt.Errorf("failed to decode json: %w", err)
+		}
+	}
+	return fs.addValues(values)
+}
+
+/*
+addValues adds decoded JSON or YAML values to the set. Scalars are added as is, and objects add their keys, so a keyed
+map compares by its keys. With keyField, the values must be objects, which are keyed on that field instead. Arrays are
+rejected.
+*/
+func (fs *fileSet) addValues(values []any) error {
+	if keyField != "" {
+		return fs.addJSONObjects(values)
+	}
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			fs.add(v, nil)
+		case json.Number:
+			fs.add(v.String(), nil)
+		case bool:
+			fs.add(fmt.Sprint(v), nil)
+		case map[string]any:
+			for key := range v {
+				fs.add(key, nil)
+			}
+		case nil:
+			continue
+		default:
+			return fmt.Errorf("unsupported value in %s: %v", fs.path, v)
+		}
+	}
+	return nil
+}
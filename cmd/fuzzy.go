@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// fuzzyMatch is a pair of elements only in one file each that are within the fuzzy flag's edit distance of each other.
+type fuzzyMatch struct {
+	A        string `json:"a"`
+	B        string `json:"b"`
+	Distance int    `json:"distance"`
+}
+
+/*
+matchFuzzy pairs the elements only in fileA with the closest element only in fileB within the fuzzy flag's Levenshtein
+distance, and the remaining elements only in fileB with the closest element only in fileA, preferring the
+lexicographically smallest on ties. Matched elements are considered present in the other file, so they are left out of
+differences and included in intersections. The pairs are stored in r.matches, sorted by the element of fileA.
+*/
+func (r *results) matchFuzzy() {
+	var onlyA, onlyB []string
+	for _, element := range r.fileSetA.set.Elements() {
+		if !r.fileSetB.set.Contains(element) {
+			onlyA = append(onlyA, element)
+		}
+	}
+	for _, element := range r.fileSetB.set.Elements() {
+		if !r.fileSetA.set.Contains(element) {
+			onlyB = append(onlyB, element)
+		}
+	}
+
+	r.fuzzyA = map[string]bool{}
+	r.fuzzyB = map[string]bool{}
+	paired := map[fuzzyMatch]bool{}
+	for _, a := range onlyA {
+		if b, d, ok := closest(a, onlyB, fuzzy); ok {
+			paired[fuzzyMatch{a, b, d}] = true
+			r.fuzzyA[a], r.fuzzyB[b] = true, true
+		}
+	}
+	for _, b := range onlyB {
+		if r.fuzzyB[b] {
+			continue
+		}
+		if a, d, ok := closest(b, onlyA, fuzzy); ok {
+			paired[fuzzyMatch{a, b, d}] = true
+			r.fuzzyA[a], r.fuzzyB[b] = true, true
+		}
+	}
+
+	r.matches = make([]fuzzyMatch, 0, len(paired))
+	for m := range paired {
+		r.matches = append(r.matches, m)
+	}
+	sort.Slice(r.matches, func(i, j int) bool {
+		if r.matches[i].A != r.matches[j].A {
+			return r.matches[i].A < r.matches[j].A
+		}
+		return r.matches[i].B < r.matches[j].B
+	})
+}
+
+// closest returns the first of the sorted candidates with the smallest edit distance to element, if it is within limit.
+func closest(element string, candidates []string, limit int) (string, int, bool) {
+	best, bestDistance := "", limit+1
+	n := utf8.RuneCountInString(element)
+	for _, candidate := range candidates {
+		// the distance is at least the difference in length, so most candidates can be skipped without comparing them
+		if abs(utf8.RuneCountInString(candidate)-n) >= bestDistance {
+			continue
+		}
+		if d := levenshtein(element, candidate, bestDistance); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, bestDistance, bestDistance <= limit
+}
+
+/*
+levenshtein returns the number of single rune insertions, deletions, and substitutions needed to turn a into b. Once
+every distance in a row of the calculation reaches limit, it stops and returns limit, since only closer strings matter.
+*/
+func levenshtein(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+/*
+writeGitDiff renders the set difference of a file changed in git, for use as a git diff driver configured with
+diff.<driver>.command. git calls the driver with seven arguments: path old-file old-hex old-mode new-file new-hex
+new-mode. The removed and added elements are written as a unified diff body, so reordered or duplicated lines don't show
+up as changes. Nothing is written if the sets are equal.
+*/
+func writeGitDiff(ctx context.Context, args []string, w io.Writer) error {
+	path, oldFile, newFile := args[0], args[1], args[4]
+	fsA := newFileSet(oldFile)
+	if err := fsA.fileToSet(ctx, parserName); err != nil {
+		return err
+	}
+	fsB := newFileSet(newFile)
+	if err := fsB.fileToSet(ctx, parserName); err != nil {
+		return err
+	}
+	rs := results{fileSetA: fsA, fileSetB: fsB, setAB: *hashset.New(), setBA: *hashset.New()}
+	rs.difference()
+	if rs.setAB.Empty() && rs.setBA.Empty() {
+		return nil
+	}
+	fmt.Fprintf(w, "diff --godiffit a/%s b/%s\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+	for _, element := range convertToSortedStringSlice(rs.setAB) {
+		fmt.Fprintf(w, "-%s\n", rs.textLine(element, &rs.fileSetA, &rs.fileSetB))
+	}
+	for _, element := range convertToSortedStringSlice(rs.setBA) {
+		fmt.Fprintf(w, "+%s\n", rs.textLine(element, &rs.fileSetB, &rs.fileSetA))
+	}
+	return nil
+}
+
+/*
+writeGitTextconv writes the sorted, unique elements of the file at path, one per line, for use as a git textconv filter
+configured with diff.<driver>.textconv. git then diffs the converted text, so only added and removed elements show up.
+*/
+func writeGitTextconv(ctx context.Context, path string, w io.Writer) error {
+	fs := newFileSet(path)
+	if err := fs.fileToSet(ctx, parserName); err != nil {
+		return err
+	}
+	rs := results{fileSetA: fs}
+	for _, element := range fs.set.Elements() {
+		fmt.Fprintln(w, rs.textLine(element, &rs.fileSetA, &rs.fileSetA))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// githubAPI is the default GitHub REST API endpoint, overridden by GITHUB_API_URL for GitHub Enterprise Server.
+const githubAPI = "https://api.github.com"
+
+/*
+openGitHub lists the members or repositories of a GitHub organization and returns them one per line. target is one of
+"ORG/members", "ORG/teams/TEAM/members", or "ORG/repos", where TEAM is the team's slug. Members are returned as logins
+and repositories by name. The token comes from the github-token flag, or else GITHUB_TOKEN or GH_TOKEN.
+*/
+func openGitHub(ctx context.Context, target string) (io.ReadCloser, error) {
+	parts := strings.Split(strings.Trim(target, "/"), "/")
+	var endpoint, field string
+	switch {
+	case len(parts) == 2 && parts[1] == "members":
+		endpoint, field = "/orgs/"+parts[0]+"/members", "login"
+	case len(parts) == 4 && parts[1] == "teams" && parts[3] == "members":
+		endpoint, field = "/orgs/"+parts[0]+"/teams/"+parts[2]+"/members", "login"
+	case len(parts) == 2 && parts[1] == "repos":
+		endpoint, field = "/orgs/"+parts[0]+"/repos", "name"
+	default:
+		return nil, fmt.Errorf("invalid github input, expected github://ORG/members, github://ORG/teams/TEAM/members, or github://ORG/repos: %s", target)
+	}
+	token := firstNonEmpty(githubToken, os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN"))
+	if token == "" {
+		return nil, fmt.Errorf("no github token set, use --github-token or GITHUB_TOKEN")
+	}
+
+	var values []string
+	next := strings.TrimSuffix(firstNonEmpty(os.Getenv("GITHUB_API_URL"), githubAPI), "/") + endpoint + "?per_page=100"
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create github request: %w", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query github: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("github request %s failed: %s", next, resp.Status)
+		}
+		var page []map[string]any
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode github response: %w", err)
+		}
+		for _, item := range page {
+			if v, ok := item[field].(string); ok {
+				values = append(values, v)
+			}
+		}
+		next = nextLink(resp)
+	}
+	l.Debug().Str("target", target).Int("results", len(values)).Msg("listed github resources")
+	return linesReader(values), nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	godiffitv1 "github.com/JakeTRogers/goDiffIt/proto/godiffit/v1"
+	"github.com/alexandrestein/gods/sets/hashset"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// diffItServer implements the DiffIt gRPC service.
+type diffItServer struct {
+	godiffitv1.UnimplementedDiffItServer
+	// mu serializes comparisons, since the comparison engine reads its options from package-level flags
+	mu sync.Mutex
+}
+
+// serveGRPC serves the DiffIt gRPC service on address until the listener fails, or ctx is canceled.
+func serveGRPC(ctx context.Context, address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	server := grpc.NewServer()
+	godiffitv1.RegisterDiffItServer(server, &diffItServer{})
+	l.Info().Str("address", lis.Addr().String()).Msg("serving gRPC")
+	go func() {
+		<-ctx.Done()
+		l.Info().Msg("stopping gRPC server")
+		server.GracefulStop()
+	}()
+	return server.Serve(lis)
+}
+
+// Compare performs the requested set operation on the two inputs and streams each resulting element.
+func (s *diffItServer) Compare(req *godiffitv1.CompareRequest, stream godiffitv1.DiffIt_CompareServer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs, err := loadResults(stream.Context(), req.GetA(), req.GetB())
+	if err != nil {
+		return err
+	}
+	switch req.GetOperation() {
+	case godiffitv1.Operation_OPERATION_UNSPECIFIED, godiffitv1.Operation_OPERATION_DIFFERENCE:
+		rs.difference()
+	case godiffitv1.Operation_OPERATION_INTERSECTION:
+		rs.intersection()
+	case godiffitv1.Operation_OPERATION_UNION:
+		rs.union()
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown operation: %s", req.GetOperation())
+	}
+	for _, set := range rs.resultSets() {
+		for _, element := range set.elements {
+			result := &godiffitv1.CompareResult{
+				Set:     set.name,
+				Value:   rs.display(element),
+				Columns: rs.columns(element, set.primary, set.secondary),
+			}
+			if err := stream.Send(result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Stats returns the sizes of the two inputs and of their differences, intersection, and union.
+func (s *diffItServer) Stats(ctx context.Context, req *godiffitv1.StatsRequest) (*godiffitv1.StatsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs, err := loadResults(ctx, req.GetA(), req.GetB())
+	if err != nil {
+		return nil, err
+	}
+	stats := rs.stats()
+	return &godiffitv1.StatsResponse{
+		SizeA: int64(stats.SizeA),
+		SizeB: int64(stats.SizeB),
+		OnlyA: int64(stats.OnlyA),
+		OnlyB: int64(stats.OnlyB),
+		Both:  int64(stats.Both),
+		Union: int64(stats.Union),
+	}, nil
+}
+
+// loadResults reads both inputs of a request into an empty results.
+func loadResults(ctx context.Context, a, b *godiffitv1.Input) (results, error) {
+	fsA, err := loadInput(ctx, a, "a")
+	if err != nil {
+		return results{}, err
+	}
+	fsB, err := loadInput(ctx, b, "b")
+	if err != nil {
+		return results{}, err
+	}
+	return results{fileSetA: fsA, fileSetB: fsB, setAB: *hashset.New(), setBA: *hashset.New()}, nil
+}
+
+/*
+loadInput reads a request input into a fileSet named after its side. Inline content is parsed directly, while paths are
+opened like command line arguments, which is only allowed if the server was started with --grpc-allow-paths.
+*/
+func loadInput(ctx context.Context, input *godiffitv1.Input, side string) (fileSet, error) {
+	switch source := input.GetSource().(type) {
+	case *godiffitv1.Input_Content:
+		fs := newFileSet(side)
+		if err := fs.readInput(ctx, bytes.NewReader(source.Content), input.GetParser()); err != nil {
+			return fs, status.Errorf(codes.InvalidArgument, "failed to read input %s: %v", side, err)
+		}
+		return fs, nil
+	case *godiffitv1.Input_Path:
+		if !grpcPaths {
+			return fileSet{}, status.Errorf(codes.PermissionDenied, "input %s: paths are not allowed, start the server with --grpc-allow-paths", side)
+		}
+		fs := newFileSet(source.Path)
+		if err := fs.fileToSet(ctx, input.GetParser()); err != nil {
+			return fs, status.Errorf(codes.InvalidArgument, "failed to read input %s: %v", side, err)
+		}
+		return fs, nil
+	}
+	return fileSet{}, status.Errorf(codes.InvalidArgument, "input %s is required", side)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+func init() {
+	registerParser("hosts", "/etc/hosts style files as hostnames, keeping the address as a column", (*fileSet).parseHosts)
+	// Ansible inventories are often named hosts too, so a file named hosts must also start with an IP address
+	registerDetector("hosts", func(name string, r *bufio.Reader) bool {
+		return name == "hosts" && looksLikeHosts(r)
+	}, nil)
+}
+
+/*
+parseHosts reads an /etc/hosts style file from r and adds each hostname and alias to the set, keeping its address as
+the element's column. Comments, including trailing ones, and blank lines are skipped.
+*/
+func (fs *fileSet) parseHosts(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fs.line++
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, name := range fields[1:] {
+			fs.add(name, []string{fields[0]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+/*
+looksLikeHosts reports whether the first line buffered in r that isn't blank or a comment starts with an IP address, as
+the lines of a hosts file do.
+*/
+func looksLikeHosts(r *bufio.Reader) bool {
+	head, _ := r.Peek(sniffLength)
+	for _, line := range strings.Split(string(head), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		// IPv6 link-local addresses may carry a zone, like fe80::1%lo0
+		address, _, _ := strings.Cut(fields[0], "%")
+		return net.ParseIP(address) != nil
+	}
+	return false
+}
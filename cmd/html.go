@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// htmlSection is a collapsible section of the HTML report, holding the records of one result set.
+type htmlSection struct {
+	Title   string
+	Header  []string
+	Records [][]string
+}
+
+// htmlStat is a labeled count or metric in the summary of the HTML report.
+type htmlStat struct {
+	Label string
+	Value string
+}
+
+// htmlReport is the data rendered by htmlTemplate.
+type htmlReport struct {
+	FileA     string
+	FileB     string
+	Operation string
+	Generated string
+	Stats     []htmlStat
+	Sections  []htmlSection
+}
+
+// htmlTemplate renders a standalone HTML report, with its styles inlined so it can be mailed or attached as one file.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>goDiffIt: {{.FileA}} and {{.FileB}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+summary { cursor: pointer; font-weight: bold; font-size: 1.1em; margin: 1em 0 0.5em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.75em; text-align: left; }
+th { background: #f4f4f4; }
+td { font-family: ui-monospace, monospace; }
+.meta { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.FileA}} and {{.FileB}}</h1>
+<p class="meta">{{.Operation}}, generated {{.Generated}}</p>
+<details open>
+<summary>Counts</summary>
+<table>
+{{- range .Stats}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- range .Sections}}
+<details open>
+<summary>{{.Title}} ({{len .Records}})</summary>
+{{- if .Records}}
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Records}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- else}}
+<p>None.</p>
+{{- end}}
+</details>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlTitles describes the result sets named by the first column of the records of each operation.
+var htmlTitles = map[string]string{
+	"A-B":     "A-B, only in %[1]s",
+	"B-A":     "B-A, only in %[2]s",
+	"A&B":     "A&B, in both %[1]s and %[2]s",
+	"A|B":     "A|B, in %[1]s or %[2]s",
+	"A~B":     "A~B, fuzzy matches of %[1]s and %[2]s",
+	"removed": "Removed, only in %[1]s",
+	"added":   "Added, only in %[2]s",
+	"changed": "Changed",
+}
+
+/*
+writeHTML writes the results as a standalone HTML report: the counts and similarity of both files, followed by a
+collapsible section per result set, like A-B and B-A, listing its elements with their kept columns.
+*/
+func (r *results) writeHTML(w io.Writer) error {
+	s := r.stats()
+	report := htmlReport{
+		FileA:     r.fileSetA.path,
+		FileB:     r.fileSetB.path,
+		Operation: r.operation,
+		Generated: time.Now().Format(time.RFC1123),
+		Stats: []htmlStat{
+			{"Size of " + r.fileSetA.path, fmt.Sprint(s.SizeA)},
+			{"Size of " + r.fileSetB.path, fmt.Sprint(s.SizeB)},
+			{"Only in " + r.fileSetA.path, fmt.Sprint(s.OnlyA)},
+			{"Only in " + r.fileSetB.path, fmt.Sprint(s.OnlyB)},
+			{"In both", fmt.Sprint(s.Both)},
+			{"Union", fmt.Sprint(s.Union)},
+			{"Jaccard index", fmt.Sprintf("%.4f", s.Jaccard)},
+			{"Sørensen–Dice coefficient", fmt.Sprintf("%.4f", s.Dice)},
+			{"Overlap coefficient", fmt.Sprintf("%.4f", s.Overlap)},
+		},
+	}
+	if multiset {
+		totals := []htmlStat{
+			{"Total lines of " + r.fileSetA.path, fmt.Sprint(s.TotalA)},
+			{"Total lines of " + r.fileSetB.path, fmt.Sprint(s.TotalB)},
+		}
+		report.Stats = append(report.Stats[:2], append(totals, report.Stats[2:]...)...)
+	}
+	switch r.operation {
+	case "values":
+		records := r.valuesRecords()
+		report.Sections = []htmlSection{{Title: "Differing totals", Header: records[0], Records: records[1:]}}
+	case "rows":
+		report.Sections = r.htmlSections(r.rowsRecords(), "removed", "added", "changed")
+	default:
+		var names []string
+		for _, rs := range r.resultSets() {
+			names = append(names, rs.name)
+		}
+		if len(r.matches) > 0 {
+			names = append(names, "A~B")
+		}
+		report.Sections = r.htmlSections(r.records(), names...)
+	}
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to write html: %w", err)
+	}
+	return nil
+}
+
+/*
+htmlSections splits records, whose first column names the result set of each record, into a section for each of names,
+in order. The first record is the header, and the naming column is left out of every section. Headers are padded with
+empty cells for records with more columns, like kept columns.
+*/
+func (r *results) htmlSections(records [][]string, names ...string) []htmlSection {
+	sections := make([]htmlSection, len(names))
+	index := map[string]int{}
+	for i, name := range names {
+		sections[i] = htmlSection{Title: fmt.Sprintf(htmlTitles[name], r.fileSetA.path, r.fileSetB.path), Header: append([]string(nil), records[0][1:]...)}
+		index[name] = i
+	}
+	for _, record := range records[1:] {
+		i, ok := index[record[0]]
+		if !ok {
+			continue
+		}
+		sections[i].Records = append(sections[i].Records, record[1:])
+		// kept columns have no name in the header
+		for len(sections[i].Header) < len(record)-1 {
+			sections[i].Header = append(sections[i].Header, "")
+		}
+	}
+	return sections
+}
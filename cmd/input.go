@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*
+openInput opens the input at path for reading. Paths with a recognized scheme prefix are read from that source instead
+of the filesystem:
+  - "-" reads stdin
+  - http:// and https:// URLs stream the response body
+  - tls://host[:port] or tls://cert.pem reads the subject alternative names of a certificate
+  - aws://ec2[/attribute] reads an attribute of each EC2 instance, like its name or private IP
+  - oci://registry/repository reads the tags, or digests, of a container repository
+  - github://ORG/members, github://ORG/teams/TEAM/members, and github://ORG/repos read GitHub organization members,
+    team members, and repository names
+  - api://host/path reads values selected by a JSON path from a paginated REST API
+  - kafka://broker/topic reads a value from each message in a bounded range of a Kafka topic
+  - journal://[unit] reads the entries of the systemd journal
+
+If cacheDir is set, inputs read from a source are cached there and reused until they are older than cacheTTL.
+*/
+func openInput(ctx context.Context, path string) (io.ReadCloser, error) {
+	if cacheDir != "" && strings.Contains(path, "://") {
+		return openCached(ctx, path)
+	}
+	return openSource(ctx, path)
+}
+
+// openSource opens the input at path, reading it from the source named by its scheme prefix, if any.
+func openSource(ctx context.Context, path string) (io.ReadCloser, error) {
+	switch {
+	case path == stdinPath:
+		return openStdin()
+	case strings.HasPrefix(path, "http://"), strings.HasPrefix(path, "https://"):
+		return openHTTP(ctx, path)
+	case strings.HasPrefix(path, "tls://"):
+		return openTLS(ctx, strings.TrimPrefix(path, "tls://"))
+	case strings.HasPrefix(path, "aws://"):
+		return openAWS(ctx, strings.TrimPrefix(path, "aws://"))
+	case strings.HasPrefix(path, "oci://"):
+		return openOCI(ctx, strings.TrimPrefix(path, "oci://"))
+	case strings.HasPrefix(path, "github://"):
+		return openGitHub(ctx, strings.TrimPrefix(path, "github://"))
+	case strings.HasPrefix(path, "api://"):
+		return openAPI(ctx, strings.TrimPrefix(path, "api://"))
+	case strings.HasPrefix(path, "kafka://"):
+		return openKafka(ctx, strings.TrimPrefix(path, "kafka://"))
+	case strings.HasPrefix(path, "journal://"):
+		return openJournal(ctx, strings.TrimPrefix(path, "journal://"))
+	}
+	return openFile(path)
+}
+
+/*
+openFile opens the file at path, decompressing it if it is compressed with gzip, bzip2, xz, or zstd. It returns an error
+if the file does not exist or cannot be opened.
+*/
+func openFile(path string) (io.ReadCloser, error) {
+	// ensure the file exists
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("file does not exist: %w", err)
+	}
+
+	// read the file
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return decompress(file, path)
+}
+
+// linesReader returns a reader producing each of lines followed by a newline.
+func linesReader(lines []string) io.ReadCloser {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return io.NopCloser(strings.NewReader(b.String()))
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+/*
+openJournal reads entries from the systemd journal with journalctl and returns one value per entry. target is an
+optional unit name followed by an optional query:
+  - since and until bound the entries by time, in any format journalctl accepts, e.g. "2024-05-01 12:00" or "-1h"
+  - priority limits the entries to a priority or range, e.g. "err" or "warning..emerg"
+  - file or directory read an exported journal, like one copied from another host, instead of the local journal
+  - field returns the named field of each entry, e.g. "_HOSTNAME", instead of "identifier: message"
+
+By default each entry is returned as "identifier: message", the same form as the syslog parser, so a journal can be
+compared with a syslog file.
+*/
+func openJournal(ctx context.Context, target string) (io.ReadCloser, error) {
+	u, err := url.Parse("journal://" + target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid journal input: %w", err)
+	}
+	q := u.Query()
+	args := []string{"--output=json", "--no-pager", "--quiet"}
+	if unit := strings.Trim(u.Host+u.Path, "/"); unit != "" {
+		args = append(args, "--unit="+unit)
+	}
+	for _, option := range []string{"since", "until", "priority", "file", "directory"} {
+		if v := q.Get(option); v != "" {
+			args = append(args, "--"+option+"="+v)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run journalctl: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	l.Debug().Strs("args", args).Msg("running journalctl")
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run journalctl: %w", err)
+	}
+
+	// journalctl is killed on errors, so it doesn't block writing the rest of the journal to a pipe nobody reads
+	stop := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	var values []string
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			stop()
+			return nil, fmt.Errorf("failed to decode journal entry: %w", err)
+		}
+		if value := journalEntryValue(entry, q.Get("field")); value != "" {
+			values = append(values, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		stop()
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("journalctl failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return linesReader(values), nil
+}
+
+// journalEntryValue returns the named field of entry, or "identifier: message" if field is empty.
+func journalEntryValue(entry map[string]any, field string) string {
+	if field != "" {
+		return journalField(entry[field])
+	}
+	message := journalField(entry["MESSAGE"])
+	identifier := journalField(entry["SYSLOG_IDENTIFIER"])
+	if identifier == "" {
+		identifier = journalField(entry["_COMM"])
+	}
+	if identifier == "" {
+		return message
+	}
+	return identifier + ": " + message
+}
+
+/*
+journalField returns the string value of a journal field in journalctl's JSON output. Fields that aren't valid UTF-8 are
+encoded as an array of bytes, and fields with several values as an array of them, in which case the first is used.
+*/
+func journalField(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) == 0 {
+			return ""
+		}
+		if _, ok := v[0].(float64); !ok {
+			return journalField(v[0])
+		}
+		b := make([]byte, 0, len(v))
+		for _, n := range v {
+			if n, ok := n.(float64); ok {
+				b = append(b, byte(n))
+			}
+		}
+		return strings.TrimRight(string(b), "\n")
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+evalJSONPath returns the values selected by path within v, a document decoded with UseNumber. The path is a dotted list
+of object keys with optional array selectors, e.g. "data.items[].id" or "$.results[0].name". A "[]" or "[*]" selector
+expands every element of an array, and "[N]" selects a single element. Keys or indexes that aren't found select nothing.
+An empty path, or "$", selects v itself.
+*/
+func evalJSONPath(v any, path string) ([]any, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	values := []any{v}
+	for path != "" {
+		var step string
+		if strings.HasPrefix(path, "[") {
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path, unclosed [: %s", path)
+			}
+			step, path = path[:end+1], path[end+1:]
+		} else {
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			step, path = path[:end], path[end:]
+		}
+		path = strings.TrimPrefix(path, ".")
+
+		var next []any
+		for _, value := range values {
+			selected, err := jsonPathStep(value, step)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, selected...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// jsonPathStep applies a single key or array selector of a json path to v.
+func jsonPathStep(v any, step string) ([]any, error) {
+	if !strings.HasPrefix(step, "[") {
+		if obj, ok := v.(map[string]any); ok {
+			if value, ok := obj[step]; ok {
+				return []any{value}, nil
+			}
+		}
+		return nil, nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, nil
+	}
+	selector := strings.TrimSpace(step[1 : len(step)-1])
+	if selector == "" || selector == "*" {
+		return arr, nil
+	}
+	i, err := strconv.Atoi(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json path selector: %s", step)
+	}
+	if i < 0 {
+		i += len(arr)
+	}
+	if i < 0 || i >= len(arr) {
+		return nil, nil
+	}
+	return []any{arr[i]}, nil
+}
+
+// decodeJSON decodes a single json document from r, keeping numbers as json.Number.
+func decodeJSON(r io.Reader) (any, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// jsonScalar returns the string form of a json value. Objects and arrays are returned as compact json, and null as "".
+func jsonScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// kafkaTimeout bounds each broker request made while consuming a topic.
+	kafkaTimeout = 30 * time.Second
+	// kafkaMaxBatch is the maximum number of bytes fetched from a partition at once.
+	kafkaMaxBatch = 10 << 20
+)
+
+/*
+openKafka consumes a bounded range of a Kafka topic and returns a value from each message, one per line. target is
+"broker[,broker...]/topic" followed by an optional query:
+  - from and to bound each partition by offset, RFC 3339 timestamp, or "earliest" and "latest", which are the defaults.
+    from is inclusive and to is exclusive.
+  - path selects the value with a json path into each message, e.g. "order.id", instead of using the whole message
+  - key uses each message's key instead of its value
+  - partition limits consumption to a single partition
+  - tls connects to the brokers over TLS
+*/
+func openKafka(ctx context.Context, target string) (io.ReadCloser, error) {
+	u, err := url.Parse("kafka://" + target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kafka input: %w", err)
+	}
+	brokers, topic := strings.Split(u.Host, ","), strings.Trim(u.Path, "/")
+	if u.Host == "" || topic == "" {
+		return nil, fmt.Errorf("invalid kafka input, expected kafka://broker/topic: %s", target)
+	}
+	q := u.Query()
+	dialer := &kafka.Dialer{Timeout: kafkaTimeout}
+	if q.Has("tls") {
+		dialer.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kafkaTimeout)
+	defer cancel()
+	var partitions []int
+	if p := q.Get("partition"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kafka partition: %s", p)
+		}
+		partitions = append(partitions, n)
+	} else if partitions, err = kafkaPartitions(ctx, dialer, brokers, topic); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, partition := range partitions {
+		conn, err := dialer.DialLeader(ctx, "tcp", brokers[0], topic, partition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to leader of %s/%d: %w", topic, partition, err)
+		}
+		read, err := readKafkaPartition(conn, q)
+		conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to consume %s/%d: %w", topic, partition, err)
+		}
+		values = append(values, read...)
+	}
+	l.Debug().Str("topic", topic).Int("partitions", len(partitions)).Int("messages", len(values)).Msg("consumed kafka topic")
+	return linesReader(values), nil
+}
+
+// kafkaPartitions returns the partition IDs of topic, asking each broker in turn until one answers.
+func kafkaPartitions(ctx context.Context, dialer *kafka.Dialer, brokers []string, topic string) ([]int, error) {
+	var errs []error
+	for _, broker := range brokers {
+		conn, err := dialer.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		found, err := conn.ReadPartitions(topic)
+		conn.Close()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids := make([]int, len(found))
+		for i, p := range found {
+			ids[i] = p.ID
+		}
+		return ids, nil
+	}
+	return nil, fmt.Errorf("failed to read partitions of %s: %w", topic, errors.Join(errs...))
+}
+
+// readKafkaPartition reads the messages of conn's partition between the from and to offsets in q and returns their values.
+func readKafkaPartition(conn *kafka.Conn, q url.Values) ([]string, error) {
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return nil, err
+	}
+	start, err := kafkaOffset(conn, q.Get("from"), first, last)
+	if err != nil {
+		return nil, err
+	}
+	end, err := kafkaOffset(conn, q.Get("to"), last, last)
+	if err != nil {
+		return nil, err
+	}
+	if start >= end {
+		return nil, nil
+	}
+	if _, err := conn.Seek(start, kafka.SeekAbsolute); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for offset := start; offset < end; {
+		if err := conn.SetReadDeadline(time.Now().Add(kafkaTimeout)); err != nil {
+			return nil, err
+		}
+		batch := conn.ReadBatch(1, kafkaMaxBatch)
+		read := 0
+		for {
+			m, err := batch.ReadMessage()
+			if err != nil || m.Offset >= end {
+				break
+			}
+			read++
+			offset = m.Offset + 1
+			value, err := kafkaValue(m, q)
+			if err != nil {
+				batch.Close()
+				return nil, err
+			}
+			values = append(values, value...)
+		}
+		if err := batch.Close(); err != nil {
+			return nil, err
+		}
+		if read == 0 {
+			break
+		}
+	}
+	return values, nil
+}
+
+/*
+kafkaOffset resolves a from or to bound into an offset of conn's partition, clamped to last. An empty bound resolves to
+def, "earliest" and "latest" to the partition's bounds, an integer to itself, and an RFC 3339 timestamp to the first
+offset written at or after it.
+*/
+func kafkaOffset(conn *kafka.Conn, bound string, def, last int64) (int64, error) {
+	offset := def
+	switch bound {
+	case "":
+	case "earliest":
+		first, _, err := conn.ReadOffsets()
+		if err != nil {
+			return 0, err
+		}
+		offset = first
+	case "latest":
+		offset = last
+	default:
+		if n, err := strconv.ParseInt(bound, 10, 64); err == nil {
+			offset = n
+			break
+		}
+		t, err := time.Parse(time.RFC3339, bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid kafka bound, expected an offset, RFC 3339 timestamp, earliest, or latest: %s", bound)
+		}
+		if offset, err = conn.ReadOffset(t); err != nil {
+			return 0, err
+		}
+		if offset < 0 {
+			offset = last
+		}
+	}
+	return min(offset, last), nil
+}
+
+// kafkaValue returns the values selected from m by the key and path options in q.
+func kafkaValue(m kafka.Message, q url.Values) ([]string, error) {
+	if q.Has("key") {
+		return []string{string(m.Key)}, nil
+	}
+	path := q.Get("path")
+	if path == "" {
+		return []string{strings.TrimRight(string(m.Value), "\r\n")}, nil
+	}
+	doc, err := decodeJSON(strings.NewReader(string(m.Value)))
+	if err != nil {
+		l.Debug().Int64("offset", m.Offset).Err(err).Msg("skipping kafka message that isn't json")
+		return nil, nil
+	}
+	selected, err := evalJSONPath(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, v := range selected {
+		if s := jsonScalar(v); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values, nil
+}
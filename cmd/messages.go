@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// printer formats the human-facing messages of reports in the language selected by setLanguage.
+var printer = message.NewPrinter(language.English)
+
+// languages are the languages reports can be printed in. The first is the fallback.
+var languages = []language.Tag{language.English, language.German, language.Spanish, language.French}
+
+// translations maps each human-facing message of reports to its translations. English messages are used as is.
+var translations = map[string]map[language.Tag]string{
+	"Difference of %s - %s:": {
+		language.German:  "Differenz von %s - %s:",
+		language.Spanish: "Diferencia de %s - %s:",
+		language.French:  "Différence de %s - %s :",
+	},
+	"Intersection of %s and %s:": {
+		language.German:  "Schnittmenge von %s und %s:",
+		language.Spanish: "Intersección de %s y %s:",
+		language.French:  "Intersection de %s et %s :",
+	},
+	"Union of %s and %s:": {
+		language.German:  "Vereinigung von %s und %s:",
+		language.Spanish: "Unión de %s y %s:",
+		language.French:  "Union de %s et %s :",
+	},
+	"closest in %s: %s, distance %d": {
+		language.German:  "am ähnlichsten in %s: %s, Abstand %d",
+		language.Spanish: "más cercano en %s: %s, distancia %d",
+		language.French:  "le plus proche dans %s : %s, distance %d",
+	},
+	"Fuzzy matches of %s and %s:": {
+		language.German:  "Unscharfe Treffer von %s und %s:",
+		language.Spanish: "Coincidencias aproximadas de %s y %s:",
+		language.French:  "Correspondances approximatives de %s et %s :",
+	},
+	"Removed (only in %s):": {
+		language.German:  "Entfernt (nur in %s):",
+		language.Spanish: "Eliminados (solo en %s):",
+		language.French:  "Supprimés (uniquement dans %s) :",
+	},
+	"Added (only in %s):": {
+		language.German:  "Hinzugefügt (nur in %s):",
+		language.Spanish: "Añadidos (solo en %s):",
+		language.French:  "Ajoutés (uniquement dans %s) :",
+	},
+	"Changed:": {
+		language.German:  "Geändert:",
+		language.Spanish: "Modificados:",
+		language.French:  "Modifiés :",
+	},
+	"Add to %s": {
+		language.German:  "Zu %s hinzufügen",
+		language.Spanish: "Añadir a %s",
+		language.French:  "Ajouter à %s",
+	},
+	"Totals of %s differing between %s and %s:": {
+		language.German:  "Abweichende Summen von %s zwischen %s und %s:",
+		language.Spanish: "Totales de %s que difieren entre %s y %s:",
+		language.French:  "Totaux de %s différents entre %s et %s :",
+	},
+	"Outliers (deltas %s):": {
+		language.German:  "Ausreißer (Abweichungen %s):",
+		language.Spanish: "Valores atípicos (diferencias %s):",
+		language.French:  "Valeurs aberrantes (écarts %s) :",
+	},
+	"beyond %s standard deviations of the mean": {
+		language.German:  "über %s Standardabweichungen vom Mittelwert",
+		language.Spanish: "a más de %s desviaciones estándar de la media",
+		language.French:  "au-delà de %s écarts-types de la moyenne",
+	},
+	"beyond %s IQRs of the quartiles": {
+		language.German:  "über %s Interquartilsabstände von den Quartilen",
+		language.Spanish: "a más de %s rangos intercuartílicos de los cuartiles",
+		language.French:  "au-delà de %s écarts interquartiles des quartiles",
+	},
+	"Size of %s": {
+		language.German:  "Größe von %s",
+		language.Spanish: "Tamaño de %s",
+		language.French:  "Taille de %s",
+	},
+	"%d only in %s, %d only in %s, %d in both": {
+		language.German:  "%d nur in %s, %d nur in %s, %d in beiden",
+		language.Spanish: "%d solo en %s, %d solo en %s, %d en ambos",
+		language.French:  "%d uniquement dans %s, %d uniquement dans %s, %d dans les deux",
+	},
+	"Total lines of %s": {
+		language.German:  "Zeilen insgesamt in %s",
+		language.Spanish: "Total de líneas de %s",
+		language.French:  "Total des lignes de %s",
+	},
+	"Only in %s": {
+		language.German:  "Nur in %s",
+		language.Spanish: "Solo en %s",
+		language.French:  "Uniquement dans %s",
+	},
+	"In both": {
+		language.German:  "In beiden",
+		language.Spanish: "En ambos",
+		language.French:  "Dans les deux",
+	},
+	"Domain": {
+		language.German:  "Domain",
+		language.Spanish: "Dominio",
+		language.French:  "Domaine",
+	},
+	"Union": {
+		language.German:  "Vereinigung",
+		language.Spanish: "Unión",
+		language.French:  "Union",
+	},
+	"Jaccard index": {
+		language.German:  "Jaccard-Index",
+		language.Spanish: "Índice de Jaccard",
+		language.French:  "Indice de Jaccard",
+	},
+	"Sørensen–Dice coefficient": {
+		language.German:  "Sørensen-Dice-Koeffizient",
+		language.Spanish: "Coeficiente de Sørensen-Dice",
+		language.French:  "Coefficient de Sørensen-Dice",
+	},
+	"Overlap coefficient": {
+		language.German:  "Überlappungskoeffizient",
+		language.Spanish: "Coeficiente de solapamiento",
+		language.French:  "Coefficient de chevauchement",
+	},
+	"Totals of %s:": {
+		language.German:  "Summen von %s:",
+		language.Spanish: "Totales de %s:",
+		language.French:  "Totaux de %s :",
+	},
+	"Deltas of differing keys": {
+		language.German:  "Abweichungen der unterschiedlichen Schlüssel",
+		language.Spanish: "Diferencias de las claves distintas",
+		language.French:  "Écarts des clés différentes",
+	},
+}
+
+func init() {
+	for key, byLanguage := range translations {
+		for tag, msg := range byLanguage {
+			message.SetString(tag, key, msg)
+		}
+	}
+}
+
+/*
+setLanguage selects the language of report messages from lang, a language tag like "de" or "fr-CA". If lang is empty,
+it is read from the LC_ALL, LC_MESSAGES, or LANG environment variables, e.g. "de_DE.UTF-8", ignoring invalid values.
+Unsupported languages fall back to the closest supported one, or English. It returns an error if lang is invalid.
+*/
+func setLanguage(lang string) error {
+	tag := language.English
+	if lang != "" {
+		t, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("invalid language %q: %w", lang, err)
+		}
+		tag = t
+	} else if env := firstNonEmpty(os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")); env != "" {
+		// POSIX locales look like de_DE.UTF-8@euro
+		env, _, _ = strings.Cut(env, ".")
+		env, _, _ = strings.Cut(env, "@")
+		if t, err := language.Parse(strings.ReplaceAll(env, "_", "-")); err == nil {
+			tag = t
+		}
+	}
+	matched, _, _ := language.NewMatcher(languages).Match(tag)
+	printer = message.NewPrinter(matched)
+	return nil
+}
+
+// translate formats the report message key in the selected language.
+func translate(key string, args ...any) string {
+	return printer.Sprintf(key, args...)
+}
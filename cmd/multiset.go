@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+/*
+multisetDifference stores the elements seen more often in fileA than in fileB in setAB, and, unless the pipe flag is
+set, those seen more often in fileB in setBA, each counted by how many more times it was seen.
+*/
+func (r *results) multisetDifference() {
+	r.counts = map[string]int{}
+	r.addExcess(&r.setAB, &r.fileSetA, &r.fileSetB)
+	if !pipe {
+		r.addExcess(&r.setBA, &r.fileSetB, &r.fileSetA)
+	}
+}
+
+// addExcess adds each element seen more often in from than in other to set, counted by the difference.
+func (r *results) addExcess(set *hashset.Set, from, other *fileSet) {
+	for _, element := range from.set.Keys() {
+		if n := from.counts[element] - other.counts[element]; n > 0 {
+			set.Add(element)
+			r.counts[element] = n
+		}
+	}
+}
+
+// multisetIntersection stores the elements seen in both files in setAB, counted by the fewer times they were seen.
+func (r *results) multisetIntersection() {
+	r.counts = map[string]int{}
+	for _, element := range r.fileSetA.set.Keys() {
+		if r.fileSetB.set.Contains(element) {
+			r.setAB.Add(element)
+			r.counts[element] = min(r.fileSetA.counts[element], r.fileSetB.counts[element])
+		}
+	}
+}
+
+// multisetUnion stores the elements of either file in setAB, counted by the most times they were seen in one of them.
+func (r *results) multisetUnion() {
+	r.counts = map[string]int{}
+	for _, fs := range []*fileSet{&r.fileSetA, &r.fileSetB} {
+		for _, element := range fs.set.Keys() {
+			r.setAB.Add(element)
+			r.counts[element] = max(r.counts[element], fs.counts[element])
+		}
+	}
+}
+
+// countColumn returns the count of element in the results as a column, or nil unless multiset is set.
+func (r *results) countColumn(element string) []string {
+	if r.counts == nil {
+		return nil
+	}
+	return []string{strconv.Itoa(r.counts[element])}
+}
+
+// total returns the number of elements seen in fs, counting duplicates, if multiset is set.
+func (fs *fileSet) total() int {
+	n := 0
+	for _, count := range fs.counts {
+		n += count
+	}
+	return n
+}
+
+// splitCount splits a line of a frequency dump, like "  5 error: disk full" from uniq -c, into its count and the rest of
+// the line. It returns false if the line doesn't start with a positive count.
+func splitCount(line string) (int, string, bool) {
+	count, rest, _ := strings.Cut(strings.TrimLeft(line, " \t"), " ")
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return 0, "", false
+	}
+	return n, strings.TrimLeft(rest, " \t"), true
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	registerParser("nmap", "nmap grepable output (-oG) as open address:port/protocol entries", (*fileSet).parseNmap)
+	registerDetector("nmap", nil, func(head []byte) bool {
+		return bytes.HasPrefix(head, []byte("# Nmap "))
+	})
+}
+
+/*
+parseNmap reads nmap grepable output, as written by -oG, from r and adds each open port to the set as
+"address:port/protocol", keeping the hostname and service as the element's columns. Hosts without a Ports section, like
+those from a ping scan, are added by address alone.
+*/
+func (fs *fileSet) parseNmap(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fs.line++
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Host: ") {
+			continue
+		}
+		sections := strings.Split(line, "\t")
+		hostFields := strings.Fields(strings.TrimPrefix(sections[0], "Host: "))
+		if len(hostFields) == 0 {
+			continue
+		}
+		address := hostFields[0]
+		hostname := ""
+		if len(hostFields) > 1 {
+			hostname = strings.Trim(hostFields[1], "()")
+		}
+		hasPorts := false
+		for _, section := range sections[1:] {
+			ports, ok := strings.CutPrefix(section, "Ports: ")
+			if !ok {
+				continue
+			}
+			hasPorts = true
+			for _, port := range strings.Split(ports, ", ") {
+				// port/state/protocol/owner/service/rpc info/version
+				parts := strings.Split(port, "/")
+				if len(parts) < 5 || parts[1] != "open" {
+					continue
+				}
+				fs.add(fmt.Sprintf("%s:%s/%s", address, parts[0], parts[2]), []string{hostname, parts[4]})
+			}
+		}
+		if !hasPorts && strings.Contains(line, "Status: Up") {
+			fs.add(address, []string{hostname})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
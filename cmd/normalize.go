@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// defaultPunct is the character class removed by the strip-punct flag when no class is given.
+const defaultPunct = "[[:punct:]]"
+
+// punctPattern matches the characters removed by the strip-punct flag, or is nil if punctuation is kept.
+var punctPattern *regexp.Regexp
+
+/*
+lowerCasers holds casers lowercasing column values using the rules of the language set with the locale flag, or is nil
+if none is set. Elements are case folded by the diffit set they are added to instead. A caser keeps state while
+lowercasing, so each goroutine takes its own from the pool.
+*/
+var lowerCasers *sync.Pool
+
+/*
+caseFolders holds casers applying Unicode case folding, which, unlike plain lowercasing, also matches spellings like
+"straße" and "STRASSE" or the Greek final and non-final sigma. Like lowerCasers, each goroutine takes its own.
+*/
+var caseFolders = &sync.Pool{New: func() any {
+	c := cases.Fold()
+	return &c
+}}
+
+/*
+setLocale configures case folding for the BCP 47 language tag in locale, e.g. "tr" or "de-CH". An empty locale keeps
+the default, language-independent Unicode case folding. It returns an error if locale is not a valid language tag.
+*/
+func setLocale(locale string) error {
+	if locale == "" {
+		lowerCasers = nil
+		return nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("invalid locale %q: %w", locale, err)
+	}
+	lowerCasers = &sync.Pool{New: func() any {
+		c := cases.Lower(tag)
+		return &c
+	}}
+	return nil
+}
+
+// foldCase lowercases the column value s according to the locale, or applies Unicode case folding if none is set.
+func foldCase(s string) string {
+	pool := caseFolders
+	if lowerCasers != nil {
+		pool = lowerCasers
+	}
+	c := pool.Get().(*cases.Caser)
+	defer pool.Put(c)
+	return c.String(s)
+}
+
+// addSpelling records that element was originally spelled as original.
+func (fs *fileSet) addSpelling(element, original string) {
+	if _, ok := fs.first[element]; !ok {
+		fs.first[element] = original
+		fs.spellings[element] = map[string]int{}
+	}
+	fs.spellings[element][original]++
+}
+
+/*
+setFQDNMode checks the mode given to the fqdn-mode flag, which selects the part of FQDNs kept by ignore-fqdn. Choosing a
+mode, if changed is true, implies ignore-fqdn. It returns an error for an unknown mode.
+*/
+func setFQDNMode(changed bool) error {
+	switch fqdnMode {
+	case diffit.FQDNHost, diffit.FQDNSubdomain, diffit.FQDNDomain:
+	default:
+		return fmt.Errorf("invalid fqdn mode: %s", fqdnMode)
+	}
+	if changed {
+		ignoreFQDN = true
+	}
+	return nil
+}
+
+/*
+setStripPunct compiles the character class given to the strip-punct flag, e.g. "[-_.,]". An empty class keeps all
+punctuation. It returns an error if class is not a valid regular expression.
+*/
+func setStripPunct(class string) error {
+	if class == "" {
+		punctPattern = nil
+		return nil
+	}
+	p, err := regexp.Compile(class)
+	if err != nil {
+		return fmt.Errorf("invalid strip-punct character class %q: %w", class, err)
+	}
+	punctPattern = p
+	return nil
+}
+
+/*
+display returns the spelling of element to print, according to caseOutput. "key" prints the element itself, "first"
+prints the first original spelling seen in fileA, then fileB, and "frequent" prints the most common original spelling
+across both files, preferring the first seen spelling on ties.
+*/
+func (r *results) display(element string) string {
+	if caseOutput == "key" {
+		return element
+	}
+	first, ok := r.fileSetA.first[element]
+	if !ok {
+		if first, ok = r.fileSetB.first[element]; !ok {
+			return element
+		}
+	}
+	if caseOutput == "first" {
+		return first
+	}
+	counts := map[string]int{}
+	for _, fs := range []*fileSet{&r.fileSetA, &r.fileSetB} {
+		for spelling, n := range fs.spellings[element] {
+			counts[spelling] += n
+		}
+	}
+	best := first
+	for spelling, n := range counts {
+		if n > counts[best] || (n == counts[best] && spelling != first && best != first && spelling < best) {
+			best = spelling
+		}
+	}
+	return best
+}
+
+/*
+setUnicodeNormalize checks the Unicode normalization form given to the unicode-normalize flag: NFC, composing
+characters, so composed and decomposed spellings like "é" and "e\u0301" are equal, or NFKC, which also replaces
+compatibility characters like ligatures and fullwidth letters with their plain equivalents. An empty form keeps elements
+as they are. It returns an error for any other form.
+*/
+func setUnicodeNormalize(form string) error {
+	switch strings.ToUpper(form) {
+	case "", "NFC", "NFKC":
+	default:
+		return fmt.Errorf("invalid unicode normalization form %q, expected NFC or NFKC", form)
+	}
+	return nil
+}
+
+// replacements are the substitutions given with the replace flag, in the order they are applied.
+var replacements []diffit.Replacement
+
+/*
+setReplacements compiles the substitutions given with the replace flag, each written as "PATTERN=>REPLACEMENT", e.g.
+'-(dev|prod)$=>' to strip an environment suffix. The replacement may refer to submatches as $1 or ${name}. It returns an
+error if a rule has no "=>" or its pattern is not a valid regular expression.
+*/
+func setReplacements(rules []string) error {
+	replacements = nil
+	for _, rule := range rules {
+		pattern, with, ok := strings.Cut(rule, "=>")
+		if !ok {
+			return fmt.Errorf("invalid replace rule %q, expected PATTERN=>REPLACEMENT", rule)
+		}
+		p, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid replace pattern %q: %w", pattern, err)
+		}
+		replacements = append(replacements, diffit.Replacement{Pattern: p, With: with})
+	}
+	return nil
+}
+
+// trimPatterns are the patterns given with the trim-regex flag, in the order they are applied.
+var trimPatterns []*regexp.Regexp
+
+/*
+setTrimPatterns compiles the patterns given with the trim-regex flag, e.g. `\d+:\s*` to remove numbered prefixes like
+"0001: ". Sets anchor each at the start and at the end of elements, so only leading and trailing content is removed.
+It returns an error if a pattern is not a valid regular expression.
+*/
+func setTrimPatterns(patterns []string) error {
+	trimPatterns = nil
+	for _, pattern := range patterns {
+		p, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid trim pattern %q: %w", pattern, err)
+		}
+		trimPatterns = append(trimPatterns, p)
+	}
+	return nil
+}
+
+// extractPatterns are the regular expressions given with the extract flag, in the order they are tried.
+var extractPatterns []*regexp.Regexp
+
+// setExtractPatterns compiles the patterns given with the extract flag. It returns an error if one is not valid.
+func setExtractPatterns(patterns []string) error {
+	extractPatterns = nil
+	for _, pattern := range patterns {
+		p, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid extract pattern %q: %w", pattern, err)
+		}
+		extractPatterns = append(extractPatterns, p)
+	}
+	return nil
+}
+
+/*
+elementOptions returns the options of the diffit sets elements are normalized and stored in, as set by the flags. The
+lines of inputs read by scanLines are also prepared and split with them.
+*/
+func elementOptions() diffit.Options {
+	opts := diffit.Options{
+		CaseSensitive:     caseSensitive,
+		Locale:            locale,
+		FoldAccents:       foldAccent,
+		UnicodeForm:       unicodeNorm,
+		Trim:              trimPatterns,
+		IgnoreFQDN:        ignoreFQDN,
+		FQDNMode:          fqdnMode,
+		Numeric:           numeric,
+		NumericRange:      numericRangeBounds.contains,
+		StripPunct:        punctPattern,
+		StripLeadingZeros: stripZeros,
+		CommentPrefix:     commentChar,
+		Extract:           extractPatterns,
+		StripTimestamps:   stripTimes,
+		Templates:         templates,
+		Replace:           replacements,
+		Delimiter:         delimiter,
+		MinLength:         minLength,
+		MaxLength:         maxLength,
+	}
+	if resolvePTRs {
+		opts.Resolve = resolvePTR
+	}
+	return opts
+}
+
+/*
+addNormalizationFlags registers the flags that select and normalize the element of each line on cmd, for subcommands
+that must read elements the same way they are compared, so they accept the flags a comparison was run with.
+*/
+func addNormalizationFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.BoolVarP(&caseSensitive, "case-sensitive", "c", false, "enable case sensitive comparison")
+	flags.StringVar(&columnName, "column", "", "name of the header column compared as the element (implies --header)")
+	flags.StringVar(&commentChar, "comment-char", "", "skip lines starting with this comment prefix, # if given without a value")
+	flags.Lookup("comment-char").NoOptDefVal = "#"
+	flags.StringVarP(&delimiter, "delimiter", "d", ",", "delimiter for CSV files, default is comma")
+	flags.StringArrayVar(&extractRules, "extract", nil, "compare the part of each line matched by this regular expression, or its first group, may be repeated")
+	flags.IntVar(&field, "field", 1, "1-based field of each delimited line compared as the element")
+	flags.BoolVar(&foldAccent, "fold-accents", false, "remove accents before comparing, so Åland and aland are equal")
+	flags.BoolVar(&hasHeader, "header", false, "treat the first line of the file as a header row")
+	flags.BoolVarP(&ignoreFQDN, "ignore-fqdn", "f", false, "ignore FQDNs")
+	flags.StringVar(&fqdnMode, "fqdn-mode", diffit.FQDNHost, "part of FQDNs kept by --ignore-fqdn: host, subdomain, or domain")
+	flags.StringVar(&locale, "locale", "", "language tag used for case insensitive comparison, e.g. tr or de")
+	flags.IntVar(&maxLength, "max-length", 0, "skip elements longer than this many characters, 0 for no limit")
+	flags.IntVar(&minLength, "min-length", 0, "skip elements shorter than this many characters")
+	flags.BoolVarP(&numeric, "numeric", "n", false, "compare elements as numbers, skipping anything that isn't one")
+	flags.StringVar(&numericRange, "numeric-range", "", "only include numbers within MIN:MAX, either bound may be omitted (implies --numeric)")
+	flags.StringArrayVar(&replaceRules, "replace", nil, "rewrite each line with a 'PATTERN=>REPLACEMENT' regular expression, may be repeated")
+	flags.BoolVar(&resolvePTRs, "resolve-ptr", false, "replace IP address elements by the host name of their PTR record")
+	flags.StringVar(&stripPunct, "strip-punct", "", "remove characters matching this regular expression character class from elements")
+	flags.Lookup("strip-punct").NoOptDefVal = defaultPunct
+	flags.BoolVarP(&stripZeros, "strip-leading-zeros", "z", false, "remove leading zeros from numbers within elements")
+	flags.BoolVar(&stripTimes, "strip-timestamps", false, "remove leading syslog, ISO 8601, or epoch timestamps from each line")
+	flags.BoolVarP(&templates, "templates", "t", false, "reduce lines to log templates by masking numbers, hex ids, IPs, and UUIDs")
+	flags.StringArrayVar(&trimRules, "trim-regex", nil, "remove leading and trailing content of each element matching this regular expression, may be repeated")
+	flags.StringVar(&unicodeNorm, "unicode-normalize", "", "convert elements to a Unicode normalization form, NFC or NFKC")
+	cmd.MarkFlagsMutuallyExclusive("field", "column")
+}
+
+/*
+prepareNormalization checks the normalization flags of cmd and compiles the patterns, ranges, and casers they give, so
+elementOptions reflects them. It returns an error if one of them is invalid.
+*/
+func prepareNormalization(cmd *cobra.Command) error {
+	if numericRange != "" {
+		bounds, err := parseNumericRange(numericRange)
+		if err != nil {
+			return err
+		}
+		numericRangeBounds = bounds
+		numeric = true
+	}
+	if err := setLocale(locale); err != nil {
+		return err
+	}
+	if err := setStripPunct(stripPunct); err != nil {
+		return err
+	}
+	if err := setFQDNMode(cmd.Flags().Changed("fqdn-mode")); err != nil {
+		return err
+	}
+	if err := setUnicodeNormalize(unicodeNorm); err != nil {
+		return err
+	}
+	if err := setReplacements(replaceRules); err != nil {
+		return err
+	}
+	if err := setExtractPatterns(extractRules); err != nil {
+		return err
+	}
+	if err := setTrimPatterns(trimRules); err != nil {
+		return err
+	}
+	if field < 1 {
+		return fmt.Errorf("invalid field: %d", field)
+	}
+	// a column is chosen by name from the header row
+	if columnName != "" {
+		hasHeader = true
+	}
+	if minLength < 0 || maxLength < 0 {
+		return fmt.Errorf("length limits must not be negative")
+	}
+	if maxLength > 0 && minLength > maxLength {
+		return fmt.Errorf("min-length %d is greater than max-length %d", minLength, maxLength)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+addJSONObjects adds each object in values to the set, keyed on the value selected by keyField, which is a field name or
+a JSON path like "metadata.name". The object's remaining top-level fields become its columns, in the order of the sorted
+field names stored in fs.header after the key field. Nested values are kept as compact JSON. It returns an error if a
+value isn't an object or the key field can't be evaluated. Objects without the key field are skipped.
+*/
+func (fs *fileSet) addJSONObjects(values []any) error {
+	objects := make([]map[string]any, 0, len(values))
+	fields := map[string]bool{}
+	for _, v := range values {
+		object, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("unsupported json value in %s with --key-field, expected an object: %v", fs.path, v)
+		}
+		objects = append(objects, object)
+		for name := range object {
+			if name != keyField {
+				fields[name] = true
+			}
+		}
+	}
+	fs.header = append([]string{keyField}, sortedKeys(fields)...)
+	for _, object := range objects {
+		keys, err := evalJSONPath(object, keyField)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 || keys[0] == nil {
+			l.Trace().Str("path", fs.path).Str("key-field", keyField).Msg("skipping object without key field")
+			continue
+		}
+		columns := make([]string, len(fs.header)-1)
+		for i, name := range fs.header[1:] {
+			if v, ok := object[name]; ok {
+				columns[i] = jsonScalar(v)
+			}
+		}
+		fs.add(jsonScalar(keys[0]), columns)
+	}
+	return nil
+}
+
+/*
+alignObjectColumns rearranges the columns of both files to the sorted union of their JSON object fields, so fields are
+compared by name even if one file has fields the other doesn't. A field missing from an object is empty.
+*/
+func (r *results) alignObjectColumns() {
+	fields := map[string]bool{}
+	for _, fs := range []*fileSet{&r.fileSetA, &r.fileSetB} {
+		for _, name := range fs.header[min(1, len(fs.header)):] {
+			fields[name] = true
+		}
+	}
+	r.alignColumns(append([]string{keyField}, sortedKeys(fields)...))
+}
+
+// alignColumns rearranges the columns of both files to header, matching them by name. A column missing from a file is
+// empty.
+func (r *results) alignColumns(header []string) {
+	for _, fs := range []*fileSet{&r.fileSetA, &r.fileSetB} {
+		index := map[string]int{}
+		for i, name := range fs.header[min(1, len(fs.header)):] {
+			index[name] = i
+		}
+		for element, columns := range fs.rows {
+			aligned := make([]string, len(header)-1)
+			for i, name := range header[1:] {
+				if j, ok := index[name]; ok && j < len(columns) {
+					aligned[i] = columns[j]
+				}
+			}
+			fs.rows[element] = aligned
+		}
+		fs.header = header
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
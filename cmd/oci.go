@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ociManifestTypes are the manifest media types accepted when resolving a tag to its digest.
+var ociManifestTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// authParamPattern matches a key="value" parameter of a WWW-Authenticate challenge.
+var authParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryClient makes authenticated requests to an OCI distribution API, caching the bearer token it is issued.
+type registryClient struct {
+	base     string // e.g. https://ghcr.io
+	username string
+	password string
+	token    string
+}
+
+/*
+openOCI lists the tags of a repository in a container registry and returns them one per line. target is
+"registry/repository", e.g. "ghcr.io/org/app" or "docker.io/library/alpine". With a "?digests" query, the manifest
+digest of each tag is returned instead, and "?insecure" uses plain HTTP for local registries. Credentials come from the
+registry-username and registry-password flags, or else the docker config file.
+*/
+func openOCI(ctx context.Context, target string) (io.ReadCloser, error) {
+	u, err := url.Parse("oci://" + target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oci input: %w", err)
+	}
+	registry, repo := u.Host, strings.Trim(u.Path, "/")
+	if registry == "" || repo == "" {
+		return nil, fmt.Errorf("invalid oci input, expected oci://registry/repository: %s", target)
+	}
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+		if !strings.Contains(repo, "/") {
+			repo = "library/" + repo
+		}
+	}
+	scheme := "https"
+	if u.Query().Has("insecure") {
+		scheme = "http"
+	}
+	c := &registryClient{base: scheme + "://" + registry, username: registryUser, password: registryPass}
+	if c.username == "" {
+		c.username, c.password = dockerConfigAuth(u.Host)
+	}
+
+	tags, err := c.tags(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	l.Debug().Str("repository", target).Int("tags", len(tags)).Msg("listed registry tags")
+	if !u.Query().Has("digests") {
+		return linesReader(tags), nil
+	}
+	digests := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		digest, err := c.digest(ctx, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, digest)
+	}
+	return linesReader(digests), nil
+}
+
+// tags returns every tag of repo, following pagination.
+func (c *registryClient) tags(ctx context.Context, repo string) ([]string, error) {
+	var tags []string
+	next := c.base + "/v2/" + repo + "/tags/list?n=1000"
+	for next != "" {
+		resp, err := c.do(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tag list: %w", err)
+		}
+		tags = append(tags, page.Tags...)
+		next = nextLink(resp)
+	}
+	return tags, nil
+}
+
+// digest returns the manifest digest of repo:tag.
+func (c *registryClient) digest(ctx context.Context, repo, tag string) (string, error) {
+	resp, err := c.do(ctx, http.MethodHead, c.base+"/v2/"+repo+"/manifests/"+tag, map[string]string{"Accept": strings.Join(ociManifestTypes, ", ")})
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("no digest returned for %s:%s", repo, tag)
+	}
+	return digest, nil
+}
+
+/*
+do makes a request to the registry, authenticating and retrying once if challenged. Bearer challenges are answered by
+requesting a token from the realm, using basic auth if credentials are set, while basic challenges use the credentials
+directly. It returns an error for any non-2xx response.
+*/
+func (c *registryClient) do(ctx context.Context, method, rawURL string, headers map[string]string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create registry request: %w", err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		switch {
+		case c.token != "":
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		case c.username != "" && attempt > 0:
+			req.SetBasicAuth(c.username, c.password)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query registry: %w", err)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			if strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+				if err := c.fetchToken(ctx, challenge); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("registry request %s %s failed: %s", method, rawURL, resp.Status)
+		}
+		return resp, nil
+	}
+}
+
+// fetchToken requests a bearer token from the realm of a WWW-Authenticate challenge.
+func (c *registryClient) fetchToken(ctx context.Context, challenge string) error {
+	params := map[string]string{}
+	for _, m := range authParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("invalid registry auth challenge: %s", challenge)
+	}
+	q := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			q.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to request registry token: %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	c.token = firstNonEmpty(token.Token, token.AccessToken)
+	return nil
+}
+
+// dockerConfigAuth returns the username and password stored for registry in the docker config file, if any.
+func dockerConfigAuth(registry string) (string, string) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return "", ""
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", ""
+	}
+	keys := []string{registry, "https://" + registry}
+	if registry == "docker.io" {
+		keys = append(keys, "https://index.docker.io/v1/")
+	}
+	for _, key := range keys {
+		if entry, ok := config.Auths[key]; ok {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return "", ""
+			}
+			user, pass, _ := strings.Cut(string(decoded), ":")
+			return user, pass
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// resultSet is a named result set along with the file sets its elements should be described from.
+type resultSet struct {
+	name      string
+	elements  []string
+	primary   *fileSet
+	secondary *fileSet
+}
+
+// jsonElement is a single element of a result set in the JSON output.
+type jsonElement struct {
+	Value    string   `json:"value"`
+	Count    int      `json:"count,omitempty"`
+	Closest  string   `json:"closest,omitempty"`
+	Distance int      `json:"distance,omitempty"`
+	Columns  []string `json:"columns,omitempty"`
+}
+
+// jsonReport is the document written by the JSON output format.
+type jsonReport struct {
+	Operation string                   `json:"operation"`
+	FileA     string                   `json:"fileA"`
+	FileB     string                   `json:"fileB"`
+	Results   map[string][]jsonElement `json:"results"`
+	Matches   []fuzzyMatch             `json:"matches,omitempty"`
+}
+
+/*
+resultSets returns the result sets for the operation performed, in output order. Difference produces "A-B" and, unless
+the pipe flag is set, "B-A". Intersection produces "A&B" and union produces "A|B". The elements violating a subset or
+superset assertion are "A-B" or "B-A".
+*/
+func (r *results) resultSets() []resultSet {
+	var sets []resultSet
+	switch r.operation {
+	case "difference":
+		sets = append(sets, resultSet{"A-B", sortElements(r.setAB, &r.fileSetA, &r.fileSetB), &r.fileSetA, &r.fileSetB})
+		if !pipe {
+			sets = append(sets, resultSet{"B-A", sortElements(r.setBA, &r.fileSetB, &r.fileSetA), &r.fileSetB, &r.fileSetA})
+		}
+	case "intersection":
+		sets = append(sets, resultSet{"A&B", sortElements(r.setAB, &r.fileSetA, &r.fileSetB), &r.fileSetA, &r.fileSetB})
+	case "union":
+		sets = append(sets, resultSet{"A|B", sortElements(r.setAB, &r.fileSetA, &r.fileSetB), &r.fileSetA, &r.fileSetB})
+	case "subset":
+		sets = append(sets, resultSet{"A-B", sortElements(r.setAB, &r.fileSetA, &r.fileSetB), &r.fileSetA, &r.fileSetB})
+	case "superset":
+		sets = append(sets, resultSet{"B-A", sortElements(r.setAB, &r.fileSetB, &r.fileSetA), &r.fileSetB, &r.fileSetA})
+	}
+	return sets
+}
+
+// write writes the results to w in the format selected by the format flag, or through the template flag's template.
+func (r *results) write(w io.Writer) error {
+	if r.operation == "curate" {
+		return r.printCurated(w)
+	}
+	// a subset or superset assertion that holds prints nothing
+	if (r.operation == "subset" || r.operation == "superset") && r.setAB.Size() == 0 {
+		return nil
+	}
+	if outputTemplate != nil {
+		return r.writeTemplate(w)
+	}
+	if r.operation == "sync" {
+		switch format {
+		case "csv":
+			return r.writeSyncCSV(w)
+		case "json":
+			return r.writeSyncJSON(w)
+		default:
+			return r.printSync(w)
+		}
+	}
+	if r.operation == "values" {
+		switch format {
+		case "csv":
+			return r.writeValuesCSV(w)
+		case "json":
+			return r.writeValuesJSON(w)
+		case "quickfix":
+			return r.writeValuesQuickfix(w)
+		case "table":
+			return writeTable(w, r.valuesRecords())
+		case "markdown":
+			return writeMarkdown(w, r.valuesRecords())
+		case "html":
+			return r.writeHTML(w)
+		default:
+			return r.printValues(w)
+		}
+	}
+	if sideBySide {
+		return r.writeSideBySide(w)
+	}
+	if r.operation == "rows" {
+		switch format {
+		case "csv":
+			return r.writeRowsCSV(w)
+		case "json":
+			return r.writeRowsJSON(w)
+		case "quickfix":
+			return r.writeRowsQuickfix(w)
+		case "table":
+			return writeTable(w, r.rowsRecords())
+		case "markdown":
+			return writeMarkdown(w, r.rowsRecords())
+		case "html":
+			return r.writeHTML(w)
+		case "diff":
+			return r.writeDiff(w)
+		default:
+			return r.printRows(w)
+		}
+	}
+	switch format {
+	case "csv":
+		return r.writeCSV(w)
+	case "json":
+		return r.writeJSON(w)
+	case "quickfix":
+		return r.writeQuickfix(w)
+	case "table":
+		return writeTable(w, r.records())
+	case "markdown":
+		return writeMarkdown(w, r.records())
+	case "html":
+		return r.writeHTML(w)
+	case "diff":
+		return r.writeDiff(w)
+	default:
+		return r.printSet(w)
+	}
+}
+
+// writeCSV writes the results as CSV with a set and value column, followed by any kept columns.
+func (r *results) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(r.records()); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+/*
+records returns the results as records with a set and value column, a count column if multiset is set, and closest
+and distance columns if suggestDist is set, followed by any kept columns. Pairs matched by --fuzzy follow in the "A~B"
+set, with the element of fileB after the element of fileA.
+*/
+func (r *results) records() [][]string {
+	records := [][]string{{"set", "value"}}
+	if r.counts != nil {
+		records[0] = append(records[0], "count")
+	}
+	if r.suggestions != nil {
+		records[0] = append(records[0], "closest", "distance")
+	}
+	for _, rs := range r.resultSets() {
+		for _, element := range rs.elements {
+			record := append([]string{rs.name, r.display(element)}, r.countColumn(element)...)
+			record = append(record, r.suggestionColumns(element)...)
+			records = append(records, append(record, r.columns(element, rs.primary, rs.secondary)...))
+		}
+	}
+	for _, m := range r.matches {
+		records = append(records, []string{"A~B", r.display(m.A), r.display(m.B)})
+	}
+	return records
+}
+
+// writeJSON writes the results as a single indented JSON document.
+func (r *results) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.jsonReport()); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// jsonReport returns the document written by the JSON output format.
+func (r *results) jsonReport() jsonReport {
+	report := jsonReport{
+		Operation: r.operation,
+		FileA:     r.fileSetA.path,
+		FileB:     r.fileSetB.path,
+		Results:   map[string][]jsonElement{},
+		Matches:   r.matches,
+	}
+	for _, rs := range r.resultSets() {
+		elements := make([]jsonElement, 0, len(rs.elements))
+		for _, element := range rs.elements {
+			s := r.suggestions[element]
+			elements = append(elements, jsonElement{Value: r.display(element), Count: r.counts[element],
+				Closest: r.display(s.Closest), Distance: s.Distance, Columns: r.columns(element, rs.primary, rs.secondary)})
+		}
+		report.Results[rs.name] = elements
+	}
+	return report
+}
+
+/*
+writeQuickfix writes each result as "file:line: value", the format of Vim's quickfix list and common problem matchers,
+so editors can jump to the line each element was read from. The location is the element's first line in the file it is
+described from, falling back to the other file, or line 1 if neither parser tracked lines.
+*/
+func (r *results) writeQuickfix(w io.Writer) error {
+	for _, rs := range r.resultSets() {
+		for _, element := range rs.elements {
+			path, line := location(element, rs.primary, rs.secondary)
+			if _, err := fmt.Fprintf(w, "%s:%d: %s\n", path, line, r.textLine(element, rs.primary, rs.secondary)); err != nil {
+				return fmt.Errorf("failed to write quickfix: %w", err)
+			}
+		}
+	}
+	for _, m := range r.matches {
+		path, line := location(m.A, &r.fileSetA, &r.fileSetB)
+		if _, err := fmt.Fprintf(w, "%s:%d: %s ~ %s\n", path, line, r.display(m.A), r.display(m.B)); err != nil {
+			return fmt.Errorf("failed to write quickfix: %w", err)
+		}
+	}
+	return nil
+}
+
+// location returns the path and line of element in primary if it was read there, otherwise in secondary.
+func location(element string, primary, secondary *fileSet) (string, int) {
+	for _, fs := range []*fileSet{primary, secondary} {
+		if line, ok := fs.lines[element]; ok {
+			return fs.path, line
+		}
+	}
+	if !primary.set.Contains(element) {
+		return secondary.path, 1
+	}
+	return primary.path, 1
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	registerParser("packages", "rpm -qa, dpkg -l, or dpkg-query -W output as package names, keeping the version as a column", (*fileSet).parsePackages)
+}
+
+/*
+parsePackages reads installed package lists from r and adds each package name to the set, keeping its version as the
+element's column, so --rows reports packages whose version differs. Supported formats are rpm -qa (name-version-release
+with an optional .arch), dpkg -l (only installed "ii" entries), and dpkg-query -W (name and version separated by a tab).
+*/
+func (fs *fileSet) parsePackages(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fs.line++
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case strings.Contains(line, "\t") && len(fields) >= 2:
+			// dpkg-query -W
+			fs.add(fields[0], []string{fields[1]})
+		case len(fields) >= 3 && len(fields[0]) == 2 && strings.HasPrefix(fields[0], "i"):
+			// dpkg -l, where anything other than "ii" isn't fully installed
+			if fields[0] == "ii" {
+				fs.add(strings.Split(fields[1], ":")[0], []string{fields[2]})
+			}
+		case len(fields) == 1:
+			// rpm -qa
+			name, version, ok := splitRPM(fields[0])
+			if !ok {
+				l.Debug().Str("path", fs.path).Str("line", line).Msg("skipping unrecognized package line")
+				continue
+			}
+			fs.add(name, []string{version})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+// splitRPM splits an rpm name-version-release string into the name and "version-release". It returns false if nvr
+// doesn't contain both a version and a release.
+func splitRPM(nvr string) (name, version string, ok bool) {
+	release := strings.LastIndex(nvr, "-")
+	if release <= 0 {
+		return "", "", false
+	}
+	v := strings.LastIndex(nvr[:release], "-")
+	if v <= 0 {
+		return "", "", false
+	}
+	return nvr[:v], nvr[v+1:], true
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// parser reads the elements of an input from r and adds them to fs, typically with fs.add or fs.addExact.
+type parser func(fs *fileSet, r io.Reader) error
+
+/*
+parserInfo is a registered parser along with a short description of the inputs it understands, and how detectFormat
+recognizes them, if it does: byName from the base name of a file, without any compression extension, and the first
+bytes buffered in r, and byContent from the first bytes of an input, without leading whitespace.
+*/
+type parserInfo struct {
+	parse       parser
+	description string
+	byName      func(name string, r *bufio.Reader) bool
+	byContent   func(head []byte) bool
+}
+
+// parsers holds every registered parser by name.
+var parsers = map[string]parserInfo{}
+
+/*
+registerParser makes a parser available to fileToSet and the parser flag under name. Parsers add elements to a fileSet,
+so they are part of this package, each registering itself from an init function in its own file, so adding one doesn't
+require changes to fileToSet or the flag. Registering the same name twice panics.
+*/
+func registerParser(name, description string, p parser) {
+	if _, ok := parsers[name]; ok {
+		panic("parser already registered: " + name)
+	}
+	parsers[name] = parserInfo{parse: p, description: description}
+}
+
+/*
+registerDetector lets detectFormat pick the parser registered under name for inputs matching byName or byContent, either
+of which may be nil. Names are matched before the extensions of the generic formats, like .json or .csv, and content
+after them, so a parser detects its inputs without changes to detectFormat. Detecting an unregistered parser panics.
+*/
+func registerDetector(name string, byName func(name string, r *bufio.Reader) bool, byContent func(head []byte) bool) {
+	info, ok := parsers[name]
+	if !ok {
+		panic("parser not registered: " + name)
+	}
+	info.byName, info.byContent = byName, byContent
+	parsers[name] = info
+}
+
+// parserNames returns the names of all registered parsers, sorted.
+func parserNames() []string {
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parserHelp returns a list of the registered parsers and their descriptions for the command's help text.
+func parserHelp() string {
+	var b strings.Builder
+	for _, name := range parserNames() {
+		fmt.Fprintf(&b, "  %-16s %s\n", name, parsers[name].description)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// setPatch describes the lines to add to and remove from fileA to make its set equal to fileB's.
+type setPatch struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+/*
+patch returns the set-patch transforming fileA's set into fileB's. Added elements become new lines of the target, so
+they are written as first read from fileB, before normalization, followed by their remaining columns, while removed
+elements are written as their keys, which are matched against the normalized lines of the target when the patch is
+applied.
+*/
+func (r *results) patch() setPatch {
+	p := setPatch{From: r.fileSetA.path, To: r.fileSetB.path, Add: []string{}, Remove: []string{}}
+	for _, element := range orderElements(r.fileSetB.set.Elements(), &r.fileSetB, &r.fileSetA) {
+		if !r.fileSetA.set.Contains(element) {
+			p.Add = append(p.Add, r.fileSetB.rawLine(element))
+		}
+	}
+	for _, element := range orderElements(r.fileSetA.set.Elements(), &r.fileSetA, &r.fileSetB) {
+		if !r.fileSetB.set.Contains(element) {
+			p.Remove = append(p.Remove, element)
+		}
+	}
+	return p
+}
+
+/*
+writePatch writes the set-patch of the results as indented JSON to the file at path, or to stdout if path is "-". It is
+encrypted if encryptTo is set.
+*/
+func (r *results) writePatch(ctx context.Context, path string) error {
+	data, err := json.MarshalIndent(r.patch(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode patch: %w", err)
+	}
+	data = append(data, '\n')
+	out, err := createOutput(ctx, path)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Abort()
+		return fmt.Errorf("failed to write patch: %w", err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// postBackoff is the delay before the first retry of a failed post, doubling with each further retry.
+const postBackoff = time.Second
+
+/*
+post sends the results as a JSON report, the same document written by --format json, to url. The request carries any
+post-header flags, and is retried up to postRetries times, with an exponential backoff, on network errors, 429, and 5xx
+responses. It returns an error if every attempt fails or the endpoint rejects the report.
+*/
+func (r *results) post(ctx context.Context, url string) error {
+	var body bytes.Buffer
+	var err error
+	switch r.operation {
+	case "rows":
+		err = r.writeRowsJSON(&body)
+	case "values":
+		err = r.writeValuesJSON(&body)
+	case "sync":
+		err = r.writeSyncJSON(&body)
+	default:
+		err = r.writeJSON(&body)
+	}
+	if err != nil {
+		return err
+	}
+
+	delay := postBackoff
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return fmt.Errorf("failed to create post request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if err := setHeaders(req, postHeaders); err != nil {
+			return err
+		}
+		resp, err := httpClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			switch {
+			case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+				l.Debug().Str("url", url).Int("status", resp.StatusCode).Msg("posted results")
+				return nil
+			case resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500:
+				return fmt.Errorf("failed to post results: %s", resp.Status)
+			}
+			err = fmt.Errorf("%s", resp.Status)
+		}
+		if attempt >= postRetries {
+			return fmt.Errorf("failed to post results after %d attempts: %w", attempt+1, err)
+		}
+		l.Warn().Err(err).Dur("retry_in", delay).Msg("failed to post results, retrying")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to post results: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// progressThreshold is the size of a local file above which its progress is reported without --progress, if stderr
+	// is a terminal.
+	progressThreshold = 256 << 20
+	// progressInterval is how often the progress of reading an input is reported.
+	progressInterval = 2 * time.Second
+)
+
+// progressReader counts the bytes and lines read from an input and periodically reports them, with the rate, on stderr.
+type progressReader struct {
+	r        io.Reader
+	path     string
+	bytes    int64
+	lines    int
+	start    time.Time
+	last     time.Time
+	reported bool
+}
+
+/*
+withProgress returns r wrapped to report the progress of reading fs.path on stderr if the progress flag is set, or if
+fs.path is a local file larger than progressThreshold and stderr is a terminal, so comparisons of multi-GB inputs don't
+look hung. The returned function reports the final count, if any progress was reported. Otherwise r is returned as is.
+*/
+func (fs *fileSet) withProgress(r io.Reader) (io.Reader, func()) {
+	if !progress && !largeInput(fs.path) {
+		return r, func() {}
+	}
+	now := time.Now()
+	p := &progressReader{r: r, path: fs.path, start: now, last: now}
+	return p, p.finish
+}
+
+// largeInput reports whether path is a local file larger than progressThreshold and stderr is a terminal.
+func largeInput(path string) bool {
+	if info, err := os.Stderr.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() > progressThreshold
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.bytes += int64(n)
+	p.lines += bytes.Count(b[:n], []byte{'\n'})
+	if time.Since(p.last) >= progressInterval {
+		p.report()
+	}
+	return n, err
+}
+
+// report prints the lines and megabytes read so far and the rate of lines per second on stderr.
+func (p *progressReader) report() {
+	p.last, p.reported = time.Now(), true
+	elapsed := p.last.Sub(p.start).Seconds()
+	fmt.Fprintf(os.Stderr, "%s: %d lines, %.1f MB read (%.0f lines/s)\n", p.path, p.lines, float64(p.bytes)/1e6,
+		float64(p.lines)/elapsed)
+}
+
+// finish reports the final count, if the input took long enough to report its progress at least once.
+func (p *progressReader) finish() {
+	if p.reported {
+		p.report()
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ptrTimeout is how long a reverse DNS lookup of --resolve-ptr may take before the address is compared as it is.
+const ptrTimeout = 5 * time.Second
+
+/*
+ptrCache holds the host name each IP address resolved to with --resolve-ptr, or the address itself if it has no PTR
+record, so addresses repeated within or across inputs are only looked up once. Both inputs are read in parallel, so it
+is guarded by a mutex.
+*/
+var ptrCache = struct {
+	sync.Mutex
+	names map[string]string
+}{names: map[string]string{}}
+
+/*
+resolvePTR returns the host name of the PTR record of element, if it is an IP address, without the trailing dot, so a
+firewall export of addresses can be compared against a hostname inventory. If an address has several PTR records, the
+first is used. Elements that aren't IP addresses, and addresses without a PTR record or whose lookup fails or takes
+longer than ptrTimeout, are returned as they are.
+*/
+func resolvePTR(element string) string {
+	ip := net.ParseIP(strings.TrimSpace(element))
+	if ip == nil {
+		return element
+	}
+	addr := ip.String()
+	ptrCache.Lock()
+	name, ok := ptrCache.names[addr]
+	ptrCache.Unlock()
+	if ok {
+		return name
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ptrTimeout)
+	defer cancel()
+	name = element
+	if names, err := net.DefaultResolver.LookupAddr(ctx, addr); err != nil {
+		l.Debug().Err(err).Str("address", addr).Msg("failed to resolve PTR record")
+	} else if len(names) > 0 {
+		name = strings.TrimSuffix(names[0], ".")
+	}
+	ptrCache.Lock()
+	ptrCache.names[addr] = name
+	ptrCache.Unlock()
+	return name
+}
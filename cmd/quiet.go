@@ -0,0 +1,44 @@
+package cmd
+
+import "errors"
+
+// errDiffer is returned by runComparison in quiet mode when the inputs differ, so it exits with status 1 like cmp -s.
+var errDiffer = errors.New("inputs differ")
+
+/*
+differs reports whether the results hold any difference between the inputs: elements only in one of them, changed rows,
+differing totals, or elements violating a containment assertion. For intersections and unions, it reports whether the
+sets of both inputs differ. Both directions of a difference are checked, even in pipe mode, which leaves B - A out of
+the results.
+*/
+func (r *results) differs() bool {
+	switch r.operation {
+	case "difference":
+		return r.setAB.Size() > 0 || r.setBA.Size() > 0 || r.reverseDiffers()
+	case "subset", "superset", "sync":
+		return r.setAB.Size() > 0 || r.setBA.Size() > 0
+	case "rows":
+		return r.setAB.Size() > 0 || r.setBA.Size() > 0 || len(r.changed) > 0
+	case "values":
+		return len(r.deltas) > 0
+	}
+	s := r.stats()
+	return s.OnlyA > 0 || s.OnlyB > 0
+}
+
+/*
+reverseDiffers reports whether fileB holds elements missing from fileA, or with multiset, seen more often than in fileA,
+leaving out those matched by --fuzzy. This is B - A, which difference doesn't compute in pipe mode.
+*/
+func (r *results) reverseDiffers() bool {
+	for _, element := range r.fileSetB.set.Keys() {
+		if multiset {
+			if r.fileSetB.counts[element] > r.fileSetA.counts[element] {
+				return true
+			}
+		} else if !r.fileSetA.set.Contains(element) && !r.fuzzyB[element] {
+			return true
+		}
+	}
+	return false
+}
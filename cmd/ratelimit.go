@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitedTransport limits the requests made by network input sources and the bandwidth of their responses.
+type rateLimitedTransport struct {
+	base      http.RoundTripper
+	requests  *rate.Limiter // nil for no request limit
+	bandwidth *rate.Limiter // nil for no bandwidth limit, in bytes per second
+}
+
+// RoundTrip waits for the request limit before sending req, and limits the rate its response body can be read at.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.requests != nil {
+		if err := t.requests.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || t.bandwidth == nil {
+		return resp, err
+	}
+	resp.Body = &rateLimitedBody{ReadCloser: resp.Body, limiter: t.bandwidth, req: req}
+	return resp, nil
+}
+
+// rateLimitedBody is a response body read no faster than its limiter allows.
+type rateLimitedBody struct {
+	io.ReadCloser
+	limiter *rate.Limiter
+	req     *http.Request
+}
+
+// Read reads at most one burst of the limiter at a time, waiting until it has been allowed.
+func (b *rateLimitedBody) Read(p []byte) (int, error) {
+	if len(p) > b.limiter.Burst() {
+		p = p[:b.limiter.Burst()]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := b.limiter.WaitN(b.req.Context(), n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+/*
+setRateLimit limits the requests per second made by network input sources to requests, and the bandwidth of their
+responses to bandwidth, a number of bytes per second with an optional K, M, or G suffix. A limit of 0 or an empty
+bandwidth is unlimited. Since a limited download can take longer than httpTimeout, the timeout only applies to receiving
+response headers when the bandwidth is limited. It returns an error if a limit is invalid.
+*/
+func setRateLimit(requests float64, bandwidth string) error {
+	if requests < 0 {
+		return fmt.Errorf("invalid rate limit: %v", requests)
+	}
+	bytes, err := parseByteSize(bandwidth)
+	if err != nil {
+		return err
+	}
+	if requests == 0 && bytes == 0 {
+		return nil
+	}
+	t := &rateLimitedTransport{base: http.DefaultTransport}
+	if requests > 0 {
+		t.requests = rate.NewLimiter(rate.Limit(requests), 1)
+	}
+	if bytes > 0 {
+		// allow reads of up to 32KiB at a time, or the whole limit if it is smaller, so slow limits don't stall
+		t.bandwidth = rate.NewLimiter(rate.Limit(bytes), int(min(bytes, 32*1024)))
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.ResponseHeaderTimeout = httpTimeout
+		t.base = base
+		httpClient.Timeout = 0
+	}
+	httpClient.Transport = t
+	return nil
+}
+
+// parseByteSize parses a number of bytes with an optional K, M, or G suffix, in powers of 1024, e.g. "512K" or "10MB".
+func parseByteSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	number := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B"), "I")
+	multiplier := int64(1)
+	if n := len(number); n > 0 {
+		switch number[n-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			number = number[:n-1]
+		}
+	}
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid byte size: %s", s)
+	}
+	return int64(size * float64(multiplier)), nil
+}
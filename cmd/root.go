@@ -5,28 +5,171 @@ package cmd
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/JakeTRogers/goDiffIt/logger"
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
 	"github.com/alexandrestein/gods/sets/hashset"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 var (
+	ansibleGroup  string
+	apiCursor     string
+	apiHeaders    []string
+	apiJSONPath   string
+	apiPaginate   string
+	apiParam      string
+	apiToken      string
+	awsProfile    string
+	awsRegion     string
+	bloom         bool
+	cacheDir      string
+	cacheTTL      time.Duration
+	caseOutput    string
 	caseSensitive bool
+	certKey       string
+	checkSubset   bool
+	checkSuperset bool
+	columnName    string
+	commentChar   string
+	counted       bool
+	checkpointDir string
+	saveInterval  time.Duration
+	dateField     int
+	dateFormat    string
+	dateRange     string
 	delimiter     string
+	emitPatch     string
+	encryptTo     []string
+	extractRules  []string
+	field         int
+	foldAccent    bool
+	filesA        []string
+	filesB        []string
+	fuzzy         int
+	forceBinary   bool
+	format        string
+	fqdnMode      string
+	gitDiff       bool
+	gitTextconv   bool
+	githubToken   string
+	groupByDomain bool
+	grpcListen    string
+	grpcPaths     bool
+	hasHeader     bool
+	hashFiles     bool
+	ignoreColumns []string
 	ignoreFQDN    bool
+	inputFormat   string
+	jsonPath      string
+	interactive   bool
+	keepColumns   bool
+	keyField      string
+	lang          string
+	locale        string
+	maxLength     int
+	minCount      int
+	multiset      bool
+	minLength     int
+	noSchedule    bool
+	noSort        bool
+	numeric       bool
+	numericRange  string
+	outliers      string
+	outputPath    string
+	parserName    string
+	compareRows   bool
+	countOnly     bool
 	pipe          bool
+	progress      bool
+	postHeaders   []string
+	postResults   string
+	postRetries   int
+	query         string
+	quiet         bool
+	queryB        string
+	rateLimit     float64
+	rateBandwidth string
+	registryPass  string
+	registryUser  string
+	replaceRules  []string
+	resolvePTRs   bool
+	showStats     bool
+	sideBySide    bool
+	signKey       string
+	skipLines     []int
+	sortOrder     string
+	summary       bool
+	skipBinary    bool
+	stdinSplit    string
+	stdioRPC      bool
+	streaming     bool
+	suggestDist   int
+	suggestSync   bool
+	templateText  string
+	stripPunct    string
+	stripTimes    bool
+	stripZeros    bool
+	templates     bool
+	tolerance     float64
+	trimRules     []string
+	tolerancePct  float64
+	unicodeNorm   string
+	valueColumn   string
+	venn          bool
+	watch         bool
 	l             = logger.GetLogger()
 )
 
 type fileSet struct {
-	path string
-	set  hashset.Set
+	path      string
+	set       *diffit.Set               // normalized elements, keyed as diffit normalizes them with elementOptions
+	rows      map[string][]string       // remaining columns of the first row seen for each element
+	header    []string                  // column names, including the key column, if hasHeader is set
+	spellings map[string]map[string]int // number of times each original spelling of an element was seen
+	first     map[string]string         // first original spelling seen for each element
+	query     string                    // SQL query run against database inputs
+	line      int                       // number of the input line being read, set by line-based parsers
+	skip      int                       // number of leading lines skipped by line-based parsers, from skipLines
+	column    int                       // 1-based field compared as the element, if found in the header by columnName
+	weight    int                       // occurrences the line being read stands for, if counted is set
+	paths     []string                  // files or globs concatenated into the set, from filesA or filesB
+	lines     map[string]int            // line number of the first occurrence of each element, if known
+	order     map[string]int            // position of the first occurrence of each element, if sortOrder is none
+	raw       map[string]string         // first unnormalized form of each element, if keepRawLines
+	counts    map[string]int            // number of times each element was seen, if minCount is above 1 or multiset is set
+	sums      map[string]float64        // total of the value column for each element, if valueColumn is set
+	domains   map[string]string         // domain suffix each element was read with, if groupByDomain is set
+	saved     time.Time                 // when fs was last checkpointed, if checkpointDir is set
+	runs      *streamRuns               // run files the set is spilled to once it is full, if streaming is set
+	prefilter *prefilter                // smaller input whose elements are held back from the runs, if prefiltered
+}
+
+// newFileSet returns an empty fileSet for the file at path.
+func newFileSet(path string) fileSet {
+	return fileSet{
+		path:      path,
+		set:       diffit.NewSet(elementOptions()),
+		rows:      map[string][]string{},
+		spellings: map[string]map[string]int{},
+		first:     map[string]string{},
+		lines:     map[string]int{},
+		order:     map[string]int{},
+		raw:       map[string]string{},
+		counts:    map[string]int{},
+		sums:      map[string]float64{},
+		domains:   map[string]string{},
+	}
 }
 
 type results struct {
@@ -35,92 +178,333 @@ type results struct {
 	operation string
 	setAB     hashset.Set
 	setBA     hashset.Set
+	changed   []rowChange
+	curated   []string     // lines kept by an interactive curation, in output order
+	deltas    []valueDelta // keys whose summed values differ
+	ignored   map[int]bool // indexes of remaining columns ignored when comparing rows
+	matches   []fuzzyMatch // pairs of elements considered present in the other file by --fuzzy
+	fuzzyA    map[string]bool
+	fuzzyB    map[string]bool
+	counts    map[string]int // occurrences of each element in the results, if multiset is set
+	// closest elements of the other file to the elements of differences, if suggestDist is set
+	suggestions map[string]suggestion
 }
 
 /*
-fileToSet reads the file specified by fs.path and adds each non-empty line to the set.
-If caseSensitive is false, it converts each line to lowercase before adding it to the set.
-If ignoreFQDN is true, it splits each line by dot and adds the first element to the set.
-Returns an error if the file does not exist or if there is an error while reading the file.
+fileToSet reads the input specified by fs.path and adds each element to the set. The input is read by the parser called
+parser, usually set with the parser flag, or else the parser for its input format, which is detected from its name and
+content unless set with the input-format flag. If fs.paths is set, each of the files they match is read instead.
+Returns an error if the input does not exist, if there is an error while reading it, or if ctx is canceled.
 */
-func (fs *fileSet) fileToSet() error {
-	// ensure the file exists
-	if _, err := os.Stat(fs.path); os.IsNotExist(err) {
-		return fmt.Errorf("file does not exist: %w", err)
+func (fs *fileSet) fileToSet(ctx context.Context, parser string) error {
+	if len(fs.paths) > 0 {
+		return fs.filesToSet(ctx, parser)
+	}
+	if info, err := os.Stat(fs.path); err == nil && info.IsDir() {
+		switch {
+		case hashFiles:
+			return fs.hashDir(ctx)
+		case parser == "pem":
+			return fs.parsePEMDir(ctx)
+		}
+		return fmt.Errorf("%s is a directory, directories are only supported with --hash or --parser pem", fs.path)
+	}
+	if driver := databaseDriver(fs.path); driver != "" {
+		return fs.queryToSet(ctx, driver)
 	}
 
-	// read the file
-	file, err := os.Open(fs.path)
+	file, err := openInput(ctx, fs.path)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return err
+	}
+	err = fs.readInput(ctx, file, parser)
+	closeInput(file, err)
+	return err
+}
+
+/*
+loadBoth reads fsA and fsB with load in parallel, since reading and normalizing each input is independent, roughly
+halving the time taken by large inputs. If either fails, their errors are joined, each naming its input.
+*/
+func loadBoth(ctx context.Context, load func(*fileSet, context.Context, string) error, fsA, fsB *fileSet) error {
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i, fs := range []*fileSet{fsA, fsB} {
+		wg.Add(1)
+		go func(i int, fs *fileSet) {
+			defer wg.Done()
+			if err := load(fs, ctx, parserName); err != nil {
+				errs[i] = fmt.Errorf("failed to load %s: %w", fs.path, err)
+			}
+		}(i, fs)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+/*
+readInput adds the elements read from r to the set using the parser called name. If name is empty, the parser for the
+input format is used instead, which is detected from fs.path and the content of r unless set with the input-format flag.
+Binary inputs are rejected, skipped, or escaped, as decided by checkBinary. Reading stops once ctx is canceled.
+*/
+func (fs *fileSet) readInput(ctx context.Context, r io.Reader, name string) error {
+	r, finish := fs.withProgress(r)
+	defer finish()
+	br, err := fs.checkBinary(bufio.NewReader(contextReader{ctx, r}))
+	if err != nil || br == nil {
+		return err
+	}
+	if name == "" {
+		name = inputFormat
+		if name == "auto" {
+			name = detectFormat(fs.path, br)
+			// --json-path selects values from structured inputs, so anything not detected as YAML is read as JSON
+			if jsonPath != "" && name != "yaml" {
+				name = "json"
+			}
+		}
+	}
+	p, ok := parsers[name]
+	if !ok {
+		return fmt.Errorf("invalid parser: %s, available parsers: %s", name, strings.Join(parserNames(), ", "))
 	}
-	defer file.Close()
+	l.Debug().Str("path", fs.path).Str("parser", name).Msg("input parser")
+	return p.parse(fs, br)
+}
 
-	// add each line to the set
-	scanner := bufio.NewScanner(file)
+/*
+scanLines adds each non-empty line read from r to the set. Each line is split by the delimiter and the field selected
+by the field flag, the first by default, is used as the element, with the remaining fields kept as its columns.
+If hasHeader is true, the first non-empty line is stored in fs.header instead of being added to the set.
+If columnName is set, the field with that name in the header is used as the element instead.
+The first fs.skip lines, like banners or headers, are skipped.
+If commentChar is set, lines starting with it, after any indentation, are skipped.
+If counted is set, each line starts with the number of times it occurred, like the output of uniq -c, which is removed.
+If extract patterns are set, each line is replaced by the part matched by the first pattern matching it, and lines
+matching none are skipped.
+If stripTimes is true, a leading timestamp is removed from each line before it is split.
+If templates is true, each line is reduced to a log template before it is split.
+If replacements are set with the replace flag, they are applied to each line, in order, before it is split.
+Comments are recognized and lines rewritten by the diffit set of fs, and split with diffit.SplitFields.
+If checkpointDir is set, the partial set is checkpointed every saveInterval, and when reading is canceled, so an
+interrupted run can resume.
+*/
+func (fs *fileSet) scanLines(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		// checkpoint the lines read so far, before reading this one
+		if checkpointDir != "" && time.Since(fs.saved) >= saveInterval {
+			if err := fs.saveCheckpoint(false, fs.line); err != nil {
+				return err
+			}
+		}
+		fs.line++
 		line := scanner.Text()
+		// skip leading lines if skipLines is set
+		if fs.line <= fs.skip {
+			continue
+		}
+		// skip commented lines if commentChar is set
+		if fs.set.IsComment(line) {
+			continue
+		}
+		// take the leading count of a frequency dump line as its occurrences
+		if counted {
+			var ok bool
+			if fs.weight, line, ok = splitCount(line); !ok {
+				l.Trace().Int("line", fs.line).Msg("skipping line without a leading count")
+				continue
+			}
+		}
+		// extract, strip timestamps, reduce to a template, and replace, as set by the flags
+		line, ok := fs.set.RewriteLine(line)
+		if !ok {
+			l.Trace().Int("line", fs.line).Msg("skipping line not matching any extract pattern")
+			continue
+		}
 		// if line is empty or contains only whitespace, skip it
 		if len(strings.TrimSpace(line)) == 0 {
 			continue
 		}
-		// convert the line to lowercase if caseSensitive is false
-		if !caseSensitive {
-			line = strings.ToLower(line)
+		// find the field named by columnName in the header row
+		if columnName != "" && fs.header == nil {
+			if err := fs.findColumn(line); err != nil {
+				return err
+			}
+		}
+		// split the line by delimiter and take the selected field as the element
+		line, columns, ok := diffit.SplitFields(line, delimiter, fs.selectedField())
+		if !ok {
+			l.Trace().Int("line", fs.line).Int("field", fs.selectedField()).Msg("skipping line without the selected field")
+			continue
 		}
-		// split the line by delimiter and take the first element
-		if strings.Contains(line, delimiter) {
-			line = strings.Split(line, delimiter)[0]
+		// the first row is the header if hasHeader is set
+		if hasHeader && fs.header == nil {
+			fs.header = append([]string{line}, columns...)
+			continue
 		}
-		// split the line by dot and take the first element if ignoreFQDN is set
-		if ignoreFQDN {
-			line = strings.Split(line, ".")[0]
+		fs.add(line, columns)
+	}
+	if err := scanner.Err(); err != nil {
+		if checkpointDir != "" && errors.Is(err, context.Canceled) {
+			if err := fs.saveCheckpoint(false, fs.line); err != nil {
+				l.Warn().Err(err).Str("path", fs.path).Msg("failed to checkpoint interrupted input")
+			}
 		}
-		fs.set.Add(line)
+		return fmt.Errorf("failed to read file: %w", err)
 	}
 	return nil
 }
 
 /*
-difference calculates the difference between two sets and stores the result in the results struct.  It iterates over
-each element in fileSetA and checks if it exists in fileSetB. If an element is not found in fileSetB, it is added to the
-resultAB set. If the 'pipe' flag is not set, it also iterates over each element in fileSetB and checks if it exists in
-fileSetA. If an element is not found in fileSetA, it is added to the resultBA set.
+add normalizes element with the diffit set of fs, configured from the flags by elementOptions, and adds it to the set.
+If dateRange is set, rows whose date field is not within the range are skipped before normalization.
+The set removes trim patterns, resolves PTR records if resolvePTRs is true, and converts the element to unicodeNorm. If
+groupByDomain is set, the domain suffix of the element at that point is recorded, since ignoreFQDN may remove it next.
+The set then canonicalizes numbers if numeric is true, skipping anything else or outside of numericRange, which gives
+the spelling of the element. Unless caseOutput is "key", the original spellings of each element are counted so they can
+be shown in the output.
+The set finally case folds the element unless caseSensitive is true, and removes accents, punctuation, and leading
+zeros as set by foldAccent, stripPunct, and stripZeros. Elements shorter than minLength or, if it is set, longer than
+maxLength are skipped.
+The element and its columns are then added with addExact.
+*/
+func (fs *fileSet) add(element string, columns []string) {
+	raw := element
+	// skip rows outside of the date range
+	if dateRange != "" && !fs.inDateRange(element, columns) {
+		return
+	}
+	n, ok := fs.set.NormalizeStages(element)
+	if !ok {
+		l.Trace().Str("element", element).Msg("skipping element filtered out by normalization")
+		return
+	}
+	// remember how the element was originally spelled
+	if caseOutput != "key" {
+		fs.addSpelling(n.Key, n.Spelling)
+	}
+	// remember the domain the element was read with, which ignoreFQDN removes
+	if _, ok := fs.domains[n.Key]; !ok && groupByDomain {
+		fs.domains[n.Key] = domainOf(n.Name)
+	}
+	// remember the unnormalized element, which a patch adds to the target
+	if _, ok := fs.raw[n.Key]; !ok && keepRawLines() {
+		fs.raw[n.Key] = raw
+	}
+	fs.addExact(n.Key, columns)
+}
+
+/*
+addExact adds element to the set without normalizing it, for parsers whose elements are identifiers, like key material,
+that must be compared exactly. If keepColumns or compareRows is true, or raw lines are kept for a patch or sync
+suggestion, the columns of the first row seen for each element are stored in fs.rows. The line being read, if the parser
+tracks it, is recorded as the element's first line. If valueColumn is set, the row's value is added to the element's
+total. If minCount is above 1, the element is only added to the set once it has been seen that many times, and if
+multiset is set, the times it has been seen are counted. A counted line is seen as many times as its count. Streamed
+inputs are spilled to their run files each time the set holds streamChunkSize elements, except for the elements of the
+smaller input they are prefiltered against, which are only recorded as seen.
+*/
+func (fs *fileSet) addExact(element string, columns []string) {
+	// hold back elements of a prefiltered input that are in the smaller input
+	if fs.prefilter != nil && fs.prefilter.contains(element) {
+		return
+	}
+	if _, ok := fs.lines[element]; !ok && fs.line > 0 {
+		fs.lines[element] = fs.line
+	}
+	// remember the input order of elements if the results keep it
+	if _, ok := fs.order[element]; !ok && sortOrder == "none" {
+		fs.order[element] = len(fs.order)
+	}
+	// keep the remaining columns of the first row seen for this element
+	if keepColumns || compareRows || keepRawLines() {
+		if _, ok := fs.rows[element]; !ok {
+			fs.rows[element] = columns
+		}
+	}
+	// add the row's value to the element's total if valueColumn is set
+	if valueColumn != "" {
+		fs.addValue(element, columns)
+	}
+	// count the element, only treating it as present once it has been seen minCount times
+	if minCount > 1 || multiset {
+		fs.counts[element] += max(1, fs.weight)
+		if fs.counts[element] < minCount {
+			return
+		}
+	}
+	fs.set.Insert(element, nil)
+	// spill a full chunk of a streamed input to its run files
+	if fs.runs != nil && fs.set.Len() >= streamChunkSize {
+		fs.spill()
+	}
+}
+
+// columns returns the remaining columns stored for element, or nil if there are none.
+func (fs *fileSet) columns(element string) []string {
+	return fs.rows[element]
+}
+
+/*
+difference stores the elements of fileSetA missing from fileSetB in setAB, and those of fileSetB missing from fileSetA
+in setBA, as found by diffit.Difference. With --fuzzy, elements matched to a similar element of the other file are left
+out.
 */
 func (r *results) difference() {
 	r.operation = "difference"
-	for _, element := range r.fileSetA.set.Values() {
-		if !r.fileSetB.set.Contains(element) {
+	if multiset {
+		r.multisetDifference()
+		return
+	}
+	if fuzzy > 0 {
+		r.matchFuzzy()
+	}
+	d := diffit.Difference(r.fileSetA.set, r.fileSetB.set)
+	for _, element := range d.Elements {
+		if !r.fuzzyA[element] {
 			r.setAB.Add(element)
 		}
 	}
 	if !pipe {
-		for _, element := range r.fileSetB.set.Values() {
-			if !r.fileSetA.set.Contains(element) {
+		for _, element := range d.Reverse {
+			if !r.fuzzyB[element] {
 				r.setBA.Add(element)
 			}
 		}
 	}
 }
 
-// union calculates the union of two sets and stores the result in the results struct.
+// union stores the union of both sets, as found by diffit.Union, in setAB.
 func (r *results) union() {
 	r.operation = "union"
-	for _, element := range r.fileSetA.set.Values() {
-		r.setAB.Add(element)
+	if multiset {
+		r.multisetUnion()
+		return
 	}
-	for _, element := range r.fileSetB.set.Values() {
+	for _, element := range diffit.Union(r.fileSetA.set, r.fileSetB.set).Elements {
 		r.setAB.Add(element)
 	}
 }
 
-// intersection calculates the intersection of two sets and stores the result in the results struct.
+/*
+intersection stores the intersection of both sets, as found by diffit.Intersection, in setAB. With --fuzzy, elements of
+fileSetA matched to a similar element of fileSetB are included.
+*/
 func (r *results) intersection() {
 	r.operation = "intersection"
-	for _, element := range r.fileSetA.set.Values() {
-		if r.fileSetB.set.Contains(element) {
-			r.setAB.Add(element)
-		}
+	if multiset {
+		r.multisetIntersection()
+		return
+	}
+	if fuzzy > 0 {
+		r.matchFuzzy()
+	}
+	for _, element := range diffit.Intersection(r.fileSetA.set, r.fileSetB.set).Elements {
+		r.setAB.Add(element)
+	}
+	for element := range r.fuzzyA {
+		r.setAB.Add(element)
 	}
 }
 
@@ -140,32 +524,242 @@ operation is "difference", showing but A - B and B - A.  If the pipe flag is tru
 only prints the first set to allow command line piping.
 It returns an error if the operation is invalid.
 */
-func (r *results) printSet() error {
+func (r *results) printSet(w io.Writer) error {
 	if !pipe {
-		switch r.operation {
-		case "intersection":
-			fmt.Printf("Intersection of %s and %s:\n", r.fileSetA.path, r.fileSetB.path)
-		case "union":
-			fmt.Printf("Union of %s and %s:\n", r.fileSetA.path, r.fileSetB.path)
-		case "difference":
-			fmt.Printf("Difference of %s - %s:\n", r.fileSetA.path, r.fileSetB.path)
-		default:
-			return fmt.Errorf("invalid operation: %s", r.operation)
+		header, err := r.header()
+		if err != nil {
+			return err
 		}
+		fmt.Fprintln(w, header)
+	}
+	primary, secondary := &r.fileSetA, &r.fileSetB
+	if r.operation == "superset" {
+		primary, secondary = secondary, primary
 	}
-	for _, element := range convertToSortedStringSlice(r.setAB) {
-		fmt.Println(element)
+	for _, element := range sortElements(r.setAB, primary, secondary) {
+		fmt.Fprintln(w, r.textLine(element, primary, secondary))
 	}
 	// for difference, print the second set showing B - A if the pipe flag is not set
 	if r.operation == "difference" && !pipe {
-		fmt.Printf("\nDifference of %s - %s:\n", r.fileSetB.path, r.fileSetA.path)
-		for _, element := range convertToSortedStringSlice(r.setBA) {
-			fmt.Println(element)
+		fmt.Fprintf(w, "\n%s\n", translate("Difference of %s - %s:", r.fileSetB.path, r.fileSetA.path))
+		for _, element := range sortElements(r.setBA, &r.fileSetB, &r.fileSetA) {
+			fmt.Fprintln(w, r.textLine(element, &r.fileSetB, &r.fileSetA))
+		}
+	}
+	if len(r.matches) > 0 && !pipe {
+		fmt.Fprintf(w, "\n%s\n", translate("Fuzzy matches of %s and %s:", r.fileSetA.path, r.fileSetB.path))
+		for _, m := range r.matches {
+			fmt.Fprintf(w, "%s ~ %s (%d)\n", r.display(m.A), r.display(m.B), m.Distance)
 		}
 	}
 	return nil
 }
 
+// header returns the header printed before the results of r.operation.
+func (r *results) header() (string, error) {
+	switch r.operation {
+	case "intersection":
+		return translate("Intersection of %s and %s:", r.fileSetA.path, r.fileSetB.path), nil
+	case "union":
+		return translate("Union of %s and %s:", r.fileSetA.path, r.fileSetB.path), nil
+	case "difference", "subset":
+		return translate("Difference of %s - %s:", r.fileSetA.path, r.fileSetB.path), nil
+	case "superset":
+		return translate("Difference of %s - %s:", r.fileSetB.path, r.fileSetA.path), nil
+	default:
+		return "", fmt.Errorf("invalid operation: %s", r.operation)
+	}
+}
+
+// textLine returns element followed by its count, if multiset is set, and its kept columns, if keepColumns is set,
+// joined by the delimiter, and by the closest element of secondary suggested for it, if any.
+func (r *results) textLine(element string, primary, secondary *fileSet) string {
+	line := r.display(element)
+	if columns := append(r.countColumn(element), r.columns(element, primary, secondary)...); len(columns) > 0 {
+		line += delimiter + strings.Join(columns, delimiter)
+	}
+	if s, ok := r.suggestions[element]; ok {
+		line += " — " + translate("closest in %s: %s, distance %d", secondary.path, r.display(s.Closest), s.Distance)
+	}
+	return line
+}
+
+/*
+columns returns the kept columns for element from the primary file set, falling back to the secondary one. It returns
+nil unless keepColumns or compareRows is set, since rows may also be stored to emit a patch.
+*/
+func (r *results) columns(element string, primary, secondary *fileSet) []string {
+	if !keepColumns && !compareRows {
+		return nil
+	}
+	if c := primary.columns(element); c != nil {
+		return c
+	}
+	return secondary.columns(element)
+}
+
+// skippedLines returns the number of leading lines skipped in the input at index, 0 for fileA or 1 for fileB.
+func skippedLines(index int) int {
+	switch len(skipLines) {
+	case 0:
+		return 0
+	case 1:
+		return skipLines[0]
+	default:
+		return skipLines[index]
+	}
+}
+
+/*
+runComparison reads the two inputs named by args and writes the results of the comparison selected by the flags, along
+with any patch or posted report. If a run is interrupted, it exits after reporting its progress.
+*/
+func runComparison(ctx context.Context, cmd *cobra.Command, args []string) error {
+	if streaming {
+		operation := "difference"
+		if cmd.Flags().Changed("intersection") {
+			operation = "intersection"
+		} else if cmd.Flags().Changed("union") {
+			operation = "union"
+		}
+		return streamComparison(ctx, operation, args)
+	}
+	load := (*fileSet).fileToSet
+	if checkpointDir != "" {
+		load = (*fileSet).checkpointedFileToSet
+	}
+	if stdinSplit != "" {
+		// the halves of stdin are named A and B in the output
+		args = []string{"A", "B"}
+	}
+	fsA := newFileSet(args[0])
+	fsA.query = query
+	fsA.skip = skippedLines(0)
+	fsA.paths = filesA
+	fsB := newFileSet(args[1])
+	fsB.query = firstNonEmpty(queryB, query)
+	fsB.skip = skippedLines(1)
+	fsB.paths = filesB
+	if stdinSplit != "" {
+		if err := splitStdin(ctx, stdinSplit, &fsA, &fsB); err != nil {
+			exitIfInterrupted(ctx)
+			return err
+		}
+	} else if err := loadBoth(ctx, load, &fsA, &fsB); err != nil {
+		exitIfInterrupted(ctx, &fsA, &fsB)
+		return err
+	}
+
+	rs := results{
+		fileSetA: fsA,
+		fileSetB: fsB,
+		setAB:    *hashset.New(),
+		setBA:    *hashset.New(),
+	}
+	l.Debug().Str("rs.fileSetA.path", fsA.path).Send()
+	l.Debug().Str("rs.fileSetB.path", fsB.path).Send()
+	if keyField != "" {
+		rs.alignObjectColumns()
+	}
+	if columnName != "" {
+		rs.alignHeaderColumns()
+	}
+	if compareRows {
+		if err := rs.compareRows(); err != nil {
+			return err
+		}
+	} else if suggestSync {
+		rs.suggestSync()
+	} else if valueColumn != "" {
+		if err := rs.compareValues(); err != nil {
+			return err
+		}
+	} else if interactive {
+		if err := rs.curate(contextReader{ctx, os.Stdin}, os.Stderr); err != nil {
+			exitIfInterrupted(ctx)
+			return err
+		}
+	} else if checkSubset || checkSuperset {
+		rs.checkContainment()
+	} else if cmd.Flags().Changed("intersection") {
+		rs.intersection()
+	} else if cmd.Flags().Changed("union") {
+		rs.union()
+	} else {
+		rs.difference()
+	}
+	if suggestDist > 0 {
+		rs.suggestClosest()
+	}
+	l.Debug().Str("rs.operation", rs.operation).Send()
+	exitIfInterrupted(ctx)
+	if err := rs.writeResults(ctx); err != nil {
+		return err
+	}
+	// quiet mode prints nothing, so a patch is only written to a file
+	if emitPatch != "" && !(quiet && emitPatch == "-") {
+		if err := rs.writePatch(ctx, emitPatch); err != nil {
+			exitIfInterrupted(ctx)
+			return err
+		}
+	}
+	if postResults != "" {
+		if err := rs.post(ctx, postResults); err != nil {
+			exitIfInterrupted(ctx)
+			return err
+		}
+	}
+	if checkpointDir != "" {
+		removeCheckpoints(fsA.path, fsB.path)
+	}
+	if quiet {
+		if rs.differs() {
+			return errDiffer
+		}
+		return nil
+	}
+	return rs.containmentError()
+}
+
+/*
+writeResults writes the results, or their stats, to the output, preceded by a Venn diagram if venn is set. Nothing is
+written in quiet mode, which only reports whether the inputs differ by its exit status.
+*/
+func (r *results) writeResults(ctx context.Context) error {
+	if quiet {
+		return nil
+	}
+	out, err := createOutput(ctx, outputPath)
+	if err != nil {
+		return err
+	}
+	if venn {
+		if err := r.writeVenn(out); err != nil {
+			out.Abort()
+			exitIfInterrupted(ctx)
+			return err
+		}
+		fmt.Fprintln(out)
+	}
+	if showStats {
+		err = r.writeStats(out)
+	} else if countOnly {
+		err = r.writeCounts(out)
+	} else {
+		err = r.write(out)
+		// assertions only list the elements violating them, which a recap of both files doesn't describe
+		if err == nil && summary && !checkSubset && !checkSuperset {
+			err = r.writeSummary(out)
+		}
+	}
+	if err != nil {
+		out.Abort()
+		exitIfInterrupted(ctx)
+		return err
+	}
+	return out.Close()
+}
+
 var rootCmd = &cobra.Command{
 	Use:     "goDiffIt [fileA] [fileB]",
 	Version: "v1.0.2",
@@ -173,16 +767,286 @@ var rootCmd = &cobra.Command{
 	Long: `goDiffIt is a CLI tool for comparing files/lists and explaining their differences. It can perform set operations such as
 union, intersection, and difference. This is very helpful for comparing data from different sources, and spotting gaps.
 
-It is case insensitive by default, but can be configured to be case sensitive with the --case-sensitive flag. It can
-also be configured to ignore fully qualified domain names (FQDNs). This is useful when one dataset is fully qualified
-and another is not.
+It is case insensitive by default, using Unicode case folding, but can be configured to be case sensitive with the
+--case-sensitive flag. --fold-accents also ignores accents, so "Åland" and "aland" are equal. Results are printed
+lowercased unless --case-output selects the first seen or most frequent original spelling instead. It can also be
+configured to ignore fully qualified domain names (FQDNs). This is useful when one dataset is fully qualified and
+another is not. --resolve-ptr replaces IP addresses by the host names of their PTR records, so an IP export can be
+compared against a hostname inventory. --fqdn-mode subdomain strips only the registrable domain, found with the public
+suffix list, so hosts in different subdomains stay distinct, and --fqdn-mode domain keeps only the registrable domain.
+--skip-lines N, or N,M per file, skips leading banner or header lines. --comment-char skips lines starting with # or
+another comment prefix. --extract compares only the part of each line matched by a regular expression, or its first
+group, trying repeated patterns in order. --replace 'PATTERN=>REPLACEMENT' rewrites each line with a regular expression,
+and may be repeated. --trim-regex removes leading and trailing content matching a regular expression from each element,
+like numbered prefixes. --unicode-normalize NFC or NFKC makes composed and decomposed spellings of the same characters,
+like "é", compare as equal. With --fuzzy N, differences and intersections treat elements within Levenshtein distance N
+of an element of the other file as present in it, and list the matched pairs. --suggest annotates differences with the
+closest element only in the other file instead. --multiset counts duplicate lines instead of collapsing them, and
+reports how many times each element differs. --counted reads frequency dumps, like uniq -c output, taking the leading
+count of each line as its occurrences.
+
+Either file can be - to read it from stdin, and --stdin-split reads both from stdin, split at a marker line. The
+repeatable --file-a and --file-b flags read several files or globs into each side instead of fileA and fileB. With
+--watch, the comparison is re-run whenever fileA or fileB changes, until it is interrupted. With -q, nothing is printed
+and the exit status is 1 if the inputs differ, 0 if not, or 2 on errors.
 
 It can also be used to compare first column CSV files, or a CSV file and a text file. The delimiter for CSV files is
-comma by default, but any character can be specified via the --delimiter flag.`,
+comma by default, but any character can be specified via the --delimiter flag. Quoted fields, like "Smith, John", may
+contain the delimiter. --field compares another column instead of the first, and --column chooses it by its name in the
+header row. The remaining columns of each row can be carried into the output with the --keep-columns flag. With the
+--rows flag, the compared column is treated as a key and rows are classified as added (key only in fileB), removed (key
+only in fileA), or changed (key in both files, but other columns differ). Volatile columns, like timestamps, can be
+excluded from that comparison with --ignore-columns, by index or by name when the files have a --header row.
+
+Instead of a file, an input can be an http:// or https:// URL, whose response body is streamed, the subject alternative
+names of a certificate, given as tls://host[:port] or tls://cert.pem, an attribute of every EC2 instance, given as
+aws://ec2[/attribute], the tags of a container repository, given as oci://registry/repository, or the members or
+repositories of a GitHub organization, given as github://ORG/members, github://ORG/teams/TEAM/members, or
+github://ORG/repos. Any JSON REST API can be read as api://host/path, with the values selected by --api-json-path and
+pages followed by --api-paginate, and messages of a Kafka topic as kafka://broker/topic?from=START&to=END. Entries of
+the systemd journal are read as journal://[unit]?since=START&until=END. --rate-limit and --rate-limit-bandwidth throttle
+the HTTP-based sources. --cache-dir caches inputs read from sources, reusing them for --cache-ttl, so repeated
+comparisons against a slow source don't read it again every run.
+
+Two databases can be compared by passing postgres://, mysql://, or sqlite:// URLs along with a --query, and --query-b
+if fileB needs a different one. The first column of each result row is the key and the remaining columns are its
+values, so --rows reports the keys whose values differ.
+
+--value-column sums a numeric column per key in each file and reports the keys whose totals differ, along with both
+totals and the delta, biggest first, e.g. to reconcile billing exports where membership alone is insufficient. Numeric
+values within --tolerance or --tolerance-percent of each other, here or with --rows, are treated as equal. --outliers
+flags deltas beyond K standard deviations or IQRs in their own section, so a few badly divergent keys are not buried in
+rounding differences. --stats prints the sizes of both files and of their overlap, along with their Jaccard index,
+Sørensen–Dice coefficient, and overlap coefficient, instead of the results, as a document with --format json or csv,
+--group-by-domain breaks those counts down per domain suffix, like corp.net or prod.example.com, --venn draws their
+overlap as a text Venn diagram, and with --value-column it also summarizes the totals of each file and the deltas of the
+differing keys with their min, max, mean, and percentiles.
+
+Headers and labels of plain text results are printed in the language given by --lang, or by the LC_ALL, LC_MESSAGES, or
+LANG environment variables. English, German, Spanish, and French are supported.
+
+Results are printed as plain text by default, or as CSV or JSON with the --format flag. They are sorted alphabetically,
+or with --sort numeric, natural, so host2 comes before host10, version, so 1.2.10 comes after 1.2.9, or none, keeping
+the input order, which --no-sort also selects. --format quickfix prints "file:line: value" for each result, pointing at
+the line it was read from, for editors like Vim or VS Code. --format table aligns the CSV columns for reading in a
+terminal, counting CJK and other wide characters as two columns. --format diff writes them as one sorted stream of "-"
+and "+" prefixed lines, for diff highlighters. --side-by-side prints them in two aligned columns, like diff -y, pairing
+fuzzy matches and changed rows. --format markdown writes them as a GitHub-flavored Markdown table, for pull requests and
+wiki pages. --format html writes a standalone HTML report with the counts of both files and a collapsible section per
+result set, for reviewing results in a browser. --summary ends plain text results with a one line recap of the counts
+only in each file and in both. --count prints the size of each result set instead, as an object like {"A-B": 3, "B-A":
+2} with --format json. --template renders the results through a Go text/template instead, given as a file or inline, for
+any other format. The JSON report can also be sent to an HTTP endpoint with --post-results, e.g. to track drift from
+scheduled runs. --emit-patch writes the additions and removals that transform fileA's set into fileB's as a JSON
+set-patch. --suggest-sync instead lists the lines to add to each file to make their sets equal. --check-subset and
+--check-superset assert that fileA is a subset or superset of fileB, printing nothing if it is, and otherwise printing
+the violating elements and exiting with status 1.
+
+--interactive builds a merged list: elements in both files are kept, and each element only in one file is shown on stderr
+to be accepted or rejected, one at a time or in bulk by regular expression. The curated lines are written to --output,
+which writes the results of any comparison to a file instead of stdout. --encrypt-to encrypts the results and any
+set-patch to age or SSH public keys with age, or to GPG key IDs with gpg, for results that must not be stored in
+plaintext. --sign writes a detached Ed25519 signature of the output and set-patch files next to them, so consumers can
+verify a report came from the scheduled job and wasn't edited.
+
+--checkpoint persists the progress of reading inputs, so an interrupted run over huge or remote inputs resumes instead
+of starting over. Ctrl-C stops a run gracefully, reporting how much of each input was read, and never leaves a partially
+written --output or --emit-patch file behind.
+
+--streaming compares plain or CSV inputs too large to hold in memory, with hundreds of millions of lines, by sorting each
+into temporary files in chunks and merge-joining them. It supports difference, intersection, and union in the text
+format, but not the options that need whole sets, like --rows, --stats, or --keep-columns. When one input is a small
+local file and the other is much larger, or with --bloom, only the smaller set is held in memory, and the larger input is
+streamed against a bloom filter of it, so only its elements missing from the smaller one are sorted.
+
+With --grpc-listen, goDiffIt instead serves the DiffIt gRPC service defined in proto/godiffit/v1/godiffit.proto, whose
+Compare and Stats RPCs compare inputs sent by clients using the options given on the command line. With --stdio-rpc,
+it reads JSON-RPC 2.0 requests from stdin, one per line, so a long-lived process can keep large inputs loaded between
+comparisons. --git-diff and --git-textconv integrate with git, so git diff shows the added and removed elements of
+list files instead of reordered lines.
+
+Input formats are detected from the file extension and content, but can be set explicitly with the --input-format flag.
+Files compressed with gzip, bzip2, xz, or zstd are decompressed transparently. A JSON input must be an array of values,
+or of objects with --key-field, which matches objects by that field so --rows lists the fields of changed objects. A
+YAML input compares the items of its lists, or keys of its maps, in each of its documents, or, with --key-field, each
+map as an object. --input-format ansible reads the hostnames of an INI or YAML Ansible inventory, or only those of
+--ansible-group and its child groups. --json-path selects the values, or objects, to compare from any JSON or YAML
+document, like items[].hostname. PEM inputs, or directories of them with --parser pem, are compared by certificate
+fingerprint, or by subject and serial number with --cert-key subject-serial. Crontabs can be compared by command alone
+with --cron-ignore-schedule. With --hash, directories are compared as the path:sha256 entries of every file below them.
+Inputs that look binary, containing NUL bytes or mostly invalid UTF-8, are an error unless --skip-binary skips them or
+--force-binary compares their lines with the non-text bytes escaped as \xNN.
+
+Specialized inputs are read by a parser, which is detected when possible or can be chosen with the --parser flag:
+`,
 	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case grpcListen != "" || stdioRPC:
+			return nil
+		case gitDiff:
+			if len(args) != 7 {
+				return fmt.Errorf("--git-diff requires the seven args git passes to a diff driver")
+			}
+			return nil
+		case gitTextconv:
+			if len(args) != 1 {
+				return fmt.Errorf("--git-textconv requires one arg: the file to convert")
+			}
+			return nil
+		case stdinSplit != "":
+			if len(args) != 0 {
+				return fmt.Errorf("--stdin-split reads both inputs from stdin and takes no args")
+			}
+			return nil
+		case len(filesA) > 0 || len(filesB) > 0:
+			if len(args) != 0 {
+				return fmt.Errorf("--file-a and --file-b replace the fileA and fileB args")
+			}
+			return nil
+		}
 		if len(args) < 2 {
 			return fmt.Errorf("requires at least two args: fileA and fileB")
 		}
+		if args[0] == stdinPath && args[1] == stdinPath {
+			return fmt.Errorf("only one input can be read from stdin, use --stdin-split to read both")
+		}
+		return nil
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch format {
+		case "text", "csv", "json", "quickfix", "table", "markdown", "html", "diff":
+		default:
+			return fmt.Errorf("invalid format: %s", format)
+		}
+		if summary && format != "text" {
+			return fmt.Errorf("--summary only supports the text format")
+		}
+		if countOnly && (format == "quickfix" || format == "html" || format == "diff") {
+			return fmt.Errorf("--count doesn't support the %s format", format)
+		}
+		// --group-by-domain breaks the --stats down by domain
+		if groupByDomain {
+			showStats = true
+		}
+		if showStats && format != "text" && format != "json" && format != "csv" {
+			return fmt.Errorf("--stats only supports the text, json, and csv formats")
+		}
+		if format == "diff" && (valueColumn != "" || suggestSync) {
+			return fmt.Errorf("--format diff can't be combined with --value-column or --suggest-sync")
+		}
+		// --no-sort is short for --sort none
+		if noSort {
+			sortOrder = "none"
+		}
+		switch sortOrder {
+		case "alpha", "numeric", "natural", "version", "none":
+		default:
+			return fmt.Errorf("invalid sort order: %s", sortOrder)
+		}
+		switch inputFormat {
+		case "auto", "plain", "csv", "json", "yaml", "ansible":
+		default:
+			return fmt.Errorf("invalid input format: %s", inputFormat)
+		}
+		if ansibleGroup != "" && inputFormat != "ansible" && parserName != "ansible" {
+			return fmt.Errorf("--ansible-group requires --input-format ansible")
+		}
+		if jsonPath != "" && inputFormat != "auto" && inputFormat != "json" && inputFormat != "yaml" {
+			return fmt.Errorf("--json-path requires --input-format json or yaml")
+		}
+		if _, ok := parsers[parserName]; parserName != "" && !ok {
+			return fmt.Errorf("invalid parser: %s, available parsers: %s", parserName, strings.Join(parserNames(), ", "))
+		}
+		if err := setRateLimit(rateLimit, rateBandwidth); err != nil {
+			return err
+		}
+		if signKey != "" && outputPath == "" {
+			return fmt.Errorf("--sign requires --output")
+		}
+		// load the signing key up front, so a bad key fails the run before any output is written
+		if err := setSigningKey(signKey); err != nil {
+			return err
+		}
+		if outliers != "" {
+			if valueColumn == "" {
+				return fmt.Errorf("--outliers requires --value-column")
+			}
+			rule, err := parseOutlierRule(outliers)
+			if err != nil {
+				return err
+			}
+			outlierRules = rule
+		}
+		switch apiPaginate {
+		case "link", "page", "cursor":
+		default:
+			return fmt.Errorf("invalid api pagination: %s", apiPaginate)
+		}
+		switch certKey {
+		case "fingerprint", "subject-serial":
+		default:
+			return fmt.Errorf("invalid cert key: %s", certKey)
+		}
+		switch caseOutput {
+		case "key", "first", "frequent":
+		default:
+			return fmt.Errorf("invalid case output: %s", caseOutput)
+		}
+		if err := prepareNormalization(cmd); err != nil {
+			return err
+		}
+		if err := setLanguage(lang); err != nil {
+			return err
+		}
+		if err := setTemplate(templateText); err != nil {
+			return err
+		}
+		if dateRange != "" {
+			bounds, err := parseDateRange(dateRange)
+			if err != nil {
+				return err
+			}
+			dateRangeBounds = bounds
+		}
+		// --bloom prefilters a streamed comparison, so it implies --streaming and its restrictions
+		mode := "--streaming"
+		if bloom {
+			streaming = true
+			mode = "--bloom"
+		}
+		if streaming {
+			// streamed sets are never held in memory as a whole, so only plain set operations are possible
+			for _, name := range []string{"rows", "suggest-sync", "interactive", "value-column", "keep-columns", "stats", "fuzzy",
+				"min-count", "emit-patch", "checkpoint", "key-field", "json-path", "parser", "stdin-split", "file-a", "hash", "multiset",
+				"suggest", "sort", "no-sort", "template", "quiet", "side-by-side", "count", "summary", "group-by-domain"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("%s can't be combined with --%s", mode, name)
+				}
+			}
+			if format != "text" || caseOutput != "key" {
+				return fmt.Errorf("%s only supports the text format with --case-output key", mode)
+			}
+		}
+		if len(skipLines) > 2 {
+			return fmt.Errorf("--skip-lines takes one count for both files or one for each")
+		}
+		for _, n := range skipLines {
+			if n < 0 {
+				return fmt.Errorf("invalid number of lines to skip: %d", n)
+			}
+		}
+		if fuzzy < 0 {
+			return fmt.Errorf("invalid fuzzy distance: %d", fuzzy)
+		}
+		if dateField < 1 {
+			return fmt.Errorf("invalid date field: %d", dateField)
+		}
+		if tolerance < 0 || tolerancePct < 0 {
+			return fmt.Errorf("tolerances must not be negative")
+		}
+		if minCount < 1 {
+			return fmt.Errorf("invalid min-count: %d", minCount)
+		}
 		return nil
 	},
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
@@ -195,39 +1059,64 @@ comma by default, but any character can be specified via the --delimiter flag.`,
 			l.Debug().Str("flag", f.Name).Str("value", f.Value.String()).Send()
 		})
 
-		fsA := fileSet{path: args[0], set: *hashset.New()}
-		if err := fsA.fileToSet(); err != nil {
-			l.Fatal().Err(err).Send()
+		ctx := cmd.Context()
+		if grpcListen != "" {
+			if err := serveGRPC(ctx, grpcListen); err != nil {
+				l.Fatal().Err(err).Send()
+			}
+			return
 		}
-		fsB := fileSet{path: args[1], set: *hashset.New()}
-		if err := fsB.fileToSet(); err != nil {
-			l.Fatal().Err(err).Send()
+		if stdioRPC {
+			if err := serveStdioRPC(ctx, os.Stdin, os.Stdout); err != nil {
+				l.Fatal().Err(err).Send()
+			}
+			return
+		}
+		if gitDiff {
+			if err := writeGitDiff(ctx, args, os.Stdout); err != nil {
+				l.Fatal().Err(err).Send()
+			}
+			return
+		}
+		if gitTextconv {
+			if err := writeGitTextconv(ctx, args[0], os.Stdout); err != nil {
+				l.Fatal().Err(err).Send()
+			}
+			return
 		}
 
-		rs := results{
-			fileSetA: fsA,
-			fileSetB: fsB,
-			setAB:    *hashset.New(),
-			setBA:    *hashset.New(),
+		paths := args
+		if len(filesA) > 0 {
+			// each side is named by its patterns in the output
+			args = []string{sideName(filesA), sideName(filesB)}
+			paths = append(append([]string(nil), filesA...), filesB...)
 		}
-		l.Debug().Str("rs.fileSetA.path", fsA.path).Send()
-		l.Debug().Str("rs.fileSetB.path", fsB.path).Send()
-		if cmd.Flags().Changed("intersection") {
-			rs.intersection()
-		} else if cmd.Flags().Changed("union") {
-			rs.union()
-		} else {
-			rs.difference()
+		if watch {
+			if err := watchInputs(ctx, paths, func() error { return runComparison(ctx, cmd, args) }); err != nil {
+				l.Fatal().Err(err).Send()
+			}
+			return
 		}
-		l.Debug().Str("rs.operation", rs.operation).Send()
-		if err := rs.printSet(); err != nil {
+		if err := runComparison(ctx, cmd, args); err != nil {
+			// like cmp -s, quiet mode exits with 1 if the inputs differ and 2 if they couldn't be compared
+			if errors.Is(err, errDiffer) {
+				os.Exit(1)
+			}
+			if quiet {
+				l.Error().Err(err).Send()
+				os.Exit(2)
+			}
 			l.Fatal().Err(err).Send()
 		}
 	},
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// parsers register themselves in init functions, so the list is only complete once they have all run
+	rootCmd.Long += parserHelp()
+	ctx, stop := interruptContext()
+	defer stop()
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -235,11 +1124,146 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolVarP(&caseSensitive, "case-sensitive", "c", false, "enable case insensitive comparison")
+	rootCmd.Flags().StringVar(&ansibleGroup, "ansible-group", "", "only compare the hosts of this group, and its children, of ansible inventories")
+	rootCmd.Flags().StringVar(&apiJSONPath, "api-json-path", "", "json path of the values in api:// responses, e.g. data[].id")
+	rootCmd.Flags().StringVar(&apiPaginate, "api-paginate", "link", "pagination of api:// inputs: link, page, or cursor")
+	rootCmd.Flags().StringVar(&apiParam, "api-param", "", "query parameter holding the page or cursor of api:// inputs")
+	rootCmd.Flags().StringVar(&apiCursor, "api-cursor", "", "json path of the next cursor in api:// responses")
+	rootCmd.Flags().StringVar(&apiToken, "api-token", "", "bearer token for api:// inputs, defaults to API_TOKEN")
+	rootCmd.Flags().StringArrayVar(&apiHeaders, "api-header", nil, "header for api:// inputs as 'NAME: VALUE', may be repeated")
+	rootCmd.Flags().StringVar(&awsProfile, "aws-profile", "", "aws profile used by aws:// inputs, defaults to AWS_PROFILE")
+	rootCmd.Flags().StringVar(&awsRegion, "aws-region", "", "aws region used by aws:// inputs, defaults to AWS_REGION or the profile's region")
+	rootCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory to cache inputs read from sources like api:// or oci:// in")
+	rootCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", time.Hour, "how long cached inputs are reused before they are read again")
+	rootCmd.Flags().StringVar(&caseOutput, "case-output", "key", "spelling printed for each element: key (normalized), first, or frequent original spelling")
+	rootCmd.Flags().BoolVar(&checkSubset, "check-subset", false, "assert that fileA is a subset of fileB, printing only the elements violating it")
+	rootCmd.Flags().BoolVar(&checkSuperset, "check-superset", false, "assert that fileA is a superset of fileB, printing only the elements violating it")
+	rootCmd.Flags().StringVar(&checkpointDir, "checkpoint", "", "directory to checkpoint reading progress in, so an interrupted run can resume")
+	rootCmd.Flags().DurationVar(&saveInterval, "checkpoint-interval", 30*time.Second, "how often to checkpoint the progress of reading plain and CSV inputs")
+	rootCmd.Flags().StringVar(&certKey, "cert-key", "fingerprint", "key for pem certificates: fingerprint or subject-serial")
+	rootCmd.Flags().BoolVar(&counted, "counted", false, "lines start with their number of occurrences, like uniq -c output, used by --multiset and --min-count")
+	rootCmd.Flags().StringVar(&commentChar, "comment-char", "", "skip lines starting with this comment prefix, # if given without a value")
+	rootCmd.Flags().Lookup("comment-char").NoOptDefVal = "#"
+	rootCmd.Flags().BoolVar(&noSchedule, "cron-ignore-schedule", false, "compare crontab entries by command only")
+	rootCmd.Flags().StringVar(&dateFormat, "date-format", "date", "layout for parsing dates: rfc3339, date, datetime, rfc1123, unix, or a Go reference layout")
+	rootCmd.Flags().StringVar(&dateRange, "date-range", "", "only include rows dated within START..END, either bound may be omitted")
+	rootCmd.Flags().IntVar(&dateField, "date-field", 1, "1-based field of each row holding the date used by --date-range, counted like --field")
 	rootCmd.Flags().StringVarP(&delimiter, "delimiter", "d", ",", "delimiter for CSV files, default is comma")
+	rootCmd.Flags().StringArrayVar(&extractRules, "extract", nil, "compare the part of each line matched by this regular expression, or its first group, may be repeated")
+	rootCmd.Flags().StringArrayVar(&filesA, "file-a", nil, "file or glob read into fileA instead of the fileA arg, can be repeated")
+	rootCmd.Flags().StringArrayVar(&filesB, "file-b", nil, "file or glob read into fileB instead of the fileB arg, can be repeated")
+	rootCmd.Flags().IntVar(&field, "field", 1, "1-based field of each delimited line compared as the element")
+	rootCmd.Flags().StringVar(&columnName, "column", "", "name of the header column compared as the element (implies --header)")
+	rootCmd.Flags().BoolVar(&foldAccent, "fold-accents", false, "remove accents before comparing, so Åland and aland are equal")
+	rootCmd.Flags().IntVar(&fuzzy, "fuzzy", 0, "consider elements within this Levenshtein distance of an element in the other file present in it")
+	rootCmd.Flags().BoolVar(&hashFiles, "hash", false, "compare directory inputs as path:sha256 entries of every file below them")
+	rootCmd.Flags().BoolVar(&hasHeader, "header", false, "treat the first line of each file as a header row")
+	rootCmd.Flags().StringSliceVar(&ignoreColumns, "ignore-columns", nil, "columns to ignore when comparing rows, by 1-based index or header name")
 	rootCmd.Flags().BoolVarP(&ignoreFQDN, "ignore-fqdn", "f", false, "ignore FQDNs")
+	rootCmd.Flags().StringVar(&fqdnMode, "fqdn-mode", diffit.FQDNHost, "part of FQDNs kept by --ignore-fqdn: host, subdomain (strip the registrable domain), or domain (keep it)")
+	rootCmd.Flags().StringVar(&inputFormat, "input-format", "auto", "input format: auto, plain, csv, json, yaml, or ansible")
+	rootCmd.Flags().StringVar(&jsonPath, "json-path", "", "json path of the values compared in json or yaml inputs, e.g. items[].hostname")
+	rootCmd.Flags().StringVar(&parserName, "parser", "", "parser for specialized inputs, e.g. pem, crontab, or hosts, overriding --input-format")
+	rootCmd.Flags().StringVar(&keyField, "key-field", "", "field or json path keying the objects of json inputs, e.g. id or metadata.name")
+	rootCmd.Flags().BoolVarP(&keepColumns, "keep-columns", "k", false, "carry the remaining columns of each row into the output")
+	rootCmd.Flags().StringVar(&format, "format", "text", "output format: text, csv, json, quickfix, table, markdown, html, or diff")
+	rootCmd.Flags().StringVar(&lang, "lang", "", "language of headers and labels, e.g. de, es, or fr (default from LANG)")
+	rootCmd.Flags().StringVar(&locale, "locale", "", "language tag used for case insensitive comparison, e.g. tr or de")
+	rootCmd.Flags().IntVar(&minCount, "min-count", 1, "only count an element as present if it appears at least this many times")
+	rootCmd.Flags().BoolVar(&multiset, "multiset", false, "count duplicate elements instead of collapsing them, comparing how many times each appears")
+	rootCmd.Flags().IntVar(&minLength, "min-length", 0, "skip elements shorter than this many characters")
+	rootCmd.Flags().IntVar(&maxLength, "max-length", 0, "skip elements longer than this many characters, 0 for no limit")
+	rootCmd.Flags().BoolVarP(&numeric, "numeric", "n", false, "compare elements as numbers, skipping anything that isn't one")
+	rootCmd.Flags().StringVar(&numericRange, "numeric-range", "", "only include numbers within MIN:MAX, either bound may be omitted (implies --numeric)")
+	rootCmd.Flags().Float64Var(&rateLimit, "rate-limit", 0, "maximum requests per second made by network inputs, 0 for no limit")
+	rootCmd.Flags().StringVar(&rateBandwidth, "rate-limit-bandwidth", "", "maximum bytes per second downloaded by network inputs, e.g. 512K or 10M")
+	rootCmd.Flags().StringVar(&githubToken, "github-token", "", "token for github:// inputs, defaults to GITHUB_TOKEN")
+	rootCmd.Flags().StringVar(&registryUser, "registry-username", "", "username for oci:// inputs, defaults to the docker config file")
+	rootCmd.Flags().StringVar(&registryPass, "registry-password", "", "password or token for oci:// inputs")
+	rootCmd.Flags().BoolVar(&gitDiff, "git-diff", false, "act as a git diff driver, taking the seven args git passes to diff.<driver>.command")
+	rootCmd.Flags().BoolVar(&gitTextconv, "git-textconv", false, "act as a git textconv filter, printing the sorted unique elements of one file")
+	rootCmd.Flags().StringVar(&grpcListen, "grpc-listen", "", "serve the gRPC API on this address, e.g. :9090, instead of comparing files")
+	rootCmd.Flags().BoolVar(&grpcPaths, "grpc-allow-paths", false, "allow gRPC clients to read inputs by path, including input sources")
+	rootCmd.Flags().BoolVar(&stdioRPC, "stdio-rpc", false, "serve JSON-RPC 2.0 requests on stdin and stdout instead of comparing files")
+	rootCmd.Flags().StringVar(&emitPatch, "emit-patch", "", "write a JSON set-patch transforming fileA into fileB to this file, or - for stdout")
+	rootCmd.Flags().StringVar(&postResults, "post-results", "", "URL to POST the JSON report to after each run")
+	rootCmd.Flags().StringArrayVar(&postHeaders, "post-header", nil, "header for --post-results as 'NAME: VALUE', may be repeated")
+	rootCmd.Flags().IntVar(&postRetries, "post-retries", 3, "number of times to retry a failed --post-results request")
+	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "print nothing and exit with status 1 if the inputs differ, 0 if they don't, or 2 on errors")
+	rootCmd.Flags().StringVar(&query, "query", "", "SQL query run against database inputs, the first column is the key")
+	rootCmd.Flags().StringVar(&queryB, "query-b", "", "SQL query run against fileB if it is a database, defaults to --query")
 	rootCmd.Flags().BoolVarP(&pipe, "pipe", "p", false, "do not print headers to allow the output to be piped")
+	rootCmd.Flags().BoolVar(&resolvePTRs, "resolve-ptr", false, "replace IP address elements by the host name of their PTR record before comparing")
+	rootCmd.Flags().StringArrayVar(&replaceRules, "replace", nil, "rewrite each line with a 'PATTERN=>REPLACEMENT' regular expression, may be repeated")
+	rootCmd.Flags().StringVar(&stripPunct, "strip-punct", "", "remove characters matching this regular expression character class from elements")
+	rootCmd.Flags().Lookup("strip-punct").NoOptDefVal = defaultPunct
+	rootCmd.Flags().BoolVarP(&stripZeros, "strip-leading-zeros", "z", false, "remove leading zeros from numbers within elements, e.g. INV-007 becomes INV-7")
+	rootCmd.Flags().BoolVar(&forceBinary, "force-binary", false, "compare binary inputs, escaping their non-text bytes as \\xNN")
+	rootCmd.Flags().IntSliceVar(&skipLines, "skip-lines", nil, "skip this many leading lines of each file, or N,M for fileA and fileB")
+	rootCmd.Flags().StringVar(&sortOrder, "sort", "alpha", "order of the results: alpha, numeric, natural, version, or none to keep the input order")
+	rootCmd.Flags().BoolVar(&noSort, "no-sort", false, "print results in the order they first appear in the inputs, same as --sort none")
+	rootCmd.Flags().BoolVar(&skipBinary, "skip-binary", false, "warn about and skip binary inputs instead of failing")
+	rootCmd.Flags().StringVar(&stdinSplit, "stdin-split", "", "read both inputs from stdin, split at the first line equal to this marker, e.g. ---")
+	rootCmd.Flags().BoolVar(&progress, "progress", false, "report the lines read and rate of each input on stderr, by default only for local files over 256 MiB")
+	rootCmd.Flags().BoolVar(&bloom, "bloom", false, "stream the larger input against a bloom filter of the smaller one, holding only the smaller set in memory (implies --streaming)")
+	rootCmd.Flags().BoolVar(&streaming, "streaming", false, "compare huge plain or CSV inputs with bounded memory by sorting them in temporary files")
+	rootCmd.Flags().BoolVar(&stripTimes, "strip-timestamps", false, "remove leading syslog, ISO 8601, or epoch timestamps from each line")
+	rootCmd.Flags().StringVar(&unicodeNorm, "unicode-normalize", "", "convert elements to a Unicode normalization form, NFC or NFKC, before comparing")
+	rootCmd.Flags().StringArrayVar(&trimRules, "trim-regex", nil, "remove leading and trailing content of each element matching this regular expression, may be repeated")
+	rootCmd.Flags().BoolVarP(&templates, "templates", "t", false, "reduce lines to log templates by masking numbers, hex ids, IPs, and UUIDs")
 	rootCmd.Flags().BoolP("intersection", "i", false, "show the intersection of the two files")
 	rootCmd.Flags().BoolP("union", "u", false, "show the union of the two files")
-	rootCmd.MarkFlagsMutuallyExclusive("intersection", "union")
+	rootCmd.Flags().BoolVarP(&compareRows, "rows", "r", false, "classify keyed rows as added, removed, or changed")
+	rootCmd.Flags().IntVar(&suggestDist, "suggest", 0, "suggest the closest element only in the other file within this edit distance for each difference, 2 if given without a value")
+	rootCmd.Flags().Lookup("suggest").NoOptDefVal = "2"
+	rootCmd.Flags().BoolVar(&suggestSync, "suggest-sync", false, "list the lines to add to each file to make their sets equal")
+	rootCmd.Flags().StringVar(&templateText, "template", "", "render the results through a Go text/template, given as a file or inline")
+	rootCmd.Flags().BoolVar(&sideBySide, "side-by-side", false, "print the results in two aligned columns, fileA and fileB, with a gutter marker like diff -y")
+	rootCmd.Flags().BoolVar(&interactive, "interactive", false, "build a merged list by accepting or rejecting each element only in one file")
+	rootCmd.Flags().StringVarP(&outputPath, "output", "o", "", "write the results to this file instead of stdout")
+	rootCmd.Flags().StringVar(&signKey, "sign", "", "ed25519 private key to write a detached signature of the output and patch files to FILE.sig")
+	rootCmd.Flags().StringArrayVar(&encryptTo, "encrypt-to", nil, "encrypt the results and patch to this age, ssh, or gpg recipient, may be repeated")
+	rootCmd.Flags().StringVar(&valueColumn, "value-column", "", "sum this numeric column, by 1-based index or header name, per key and report differing totals")
+	rootCmd.Flags().Float64Var(&tolerance, "tolerance", 0, "treat numeric values differing by at most this much as equal")
+	rootCmd.Flags().Float64Var(&tolerancePct, "tolerance-percent", 0, "treat numeric values differing by at most this percentage of fileA's value as equal")
+	rootCmd.Flags().StringVar(&outliers, "outliers", "", "flag outlying --value-column deltas: stddev[:K] (default 3) or iqr[:K] (default 1.5)")
+	rootCmd.Flags().BoolVar(&countOnly, "count", false, "print the number of elements in each result set instead of the elements")
+	rootCmd.Flags().BoolVar(&summary, "summary", false, "end the results with a one line recap of the counts only in each file and in both")
+	rootCmd.Flags().BoolVar(&showStats, "stats", false, "print the sizes of both files and of their overlap instead of the results")
+	rootCmd.Flags().BoolVar(&groupByDomain, "group-by-domain", false, "break the --stats down by domain suffix, e.g. corp.net or prod.example.com (implies --stats)")
+	rootCmd.Flags().BoolVar(&venn, "venn", false, "print a text Venn diagram of the element counts before the results")
+	rootCmd.Flags().BoolVar(&watch, "watch", false, "re-run the comparison whenever fileA or fileB changes")
+	rootCmd.MarkFlagsMutuallyExclusive("intersection", "union", "rows", "suggest-sync", "interactive", "value-column", "check-subset", "check-superset")
+	rootCmd.MarkFlagsMutuallyExclusive("force-binary", "skip-binary")
+	rootCmd.MarkFlagsMutuallyExclusive("field", "column")
+	rootCmd.MarkFlagsMutuallyExclusive("sort", "no-sort")
+	rootCmd.MarkFlagsMutuallyExclusive("multiset", "min-count")
+	rootCmd.MarkFlagsMutuallyExclusive("multiset", "fuzzy")
+	rootCmd.MarkFlagsRequiredTogether("file-a", "file-b")
+	rootCmd.MarkFlagsMutuallyExclusive("file-a", "stdin-split")
+	rootCmd.MarkFlagsMutuallyExclusive("file-a", "checkpoint")
+	rootCmd.MarkFlagsMutuallyExclusive("stdin-split", "checkpoint")
+	rootCmd.MarkFlagsMutuallyExclusive("watch", "interactive")
+	// the curated list is only known once the prompts are answered, and has no JSON report to post
+	rootCmd.MarkFlagsMutuallyExclusive("post-results", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "watch")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("watch", "stdin-split")
+	rootCmd.MarkFlagsMutuallyExclusive("venn", "format")
+	rootCmd.MarkFlagsMutuallyExclusive("template", "format")
+	rootCmd.MarkFlagsMutuallyExclusive("template", "stats")
+	rootCmd.MarkFlagsMutuallyExclusive("summary", "count", "stats", "template", "suggest-sync", "value-column", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("count", "stats", "template", "side-by-side", "suggest-sync", "value-column", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("template", "rows", "suggest-sync", "value-column", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("stats", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("group-by-domain", "count", "summary", "template", "side-by-side", "suggest-sync", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("side-by-side", "format")
+	rootCmd.MarkFlagsMutuallyExclusive("side-by-side", "template")
+	rootCmd.MarkFlagsMutuallyExclusive("side-by-side", "suggest-sync", "value-column", "interactive")
+	rootCmd.MarkFlagsMutuallyExclusive("grpc-listen", "stdio-rpc", "git-diff", "git-textconv")
+	// --pipe leaves B - A out of differences, which clients of the gRPC and JSON-RPC servers expect in every response
+	rootCmd.MarkFlagsMutuallyExclusive("grpc-listen", "pipe")
+	rootCmd.MarkFlagsMutuallyExclusive("stdio-rpc", "pipe")
 	rootCmd.PersistentFlags().CountP("verbose", "v", "verbose output")
+	registerCompletions()
 }
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+)
+
+// columnDiff is a single column whose value differs between the rows of fileA and fileB.
+type columnDiff struct {
+	index int // index into the remaining columns, i.e. excluding the key
+	a     string
+	b     string
+}
+
+// rowChange is a key present in both files whose remaining columns differ.
+type rowChange struct {
+	key   string
+	diffs []columnDiff
+}
+
+// jsonRow is an added or removed row in the JSON output.
+type jsonRow struct {
+	Key     string   `json:"key"`
+	Columns []string `json:"columns"`
+}
+
+// jsonColumnDiff is a differing column of a changed row in the JSON output.
+type jsonColumnDiff struct {
+	Column string `json:"column"`
+	A      string `json:"a"`
+	B      string `json:"b"`
+}
+
+// jsonRowChange is a changed row in the JSON output.
+type jsonRowChange struct {
+	Key         string           `json:"key"`
+	Differences []jsonColumnDiff `json:"differences"`
+}
+
+// jsonRowsReport is the document written by the JSON output format when comparing rows.
+type jsonRowsReport struct {
+	Operation string          `json:"operation"`
+	FileA     string          `json:"fileA"`
+	FileB     string          `json:"fileB"`
+	Added     []jsonRow       `json:"added"`
+	Removed   []jsonRow       `json:"removed"`
+	Changed   []jsonRowChange `json:"changed"`
+}
+
+/*
+compareRows classifies the keyed rows of both files. Keys only in fileSetA are removed and stored in setAB, keys only in
+fileSetB are added and stored in setBA. Keys present in both files whose remaining columns differ are stored in changed,
+along with the differing columns. Columns listed in ignoreColumns are not compared. It returns an error if an ignored
+column cannot be resolved.
+*/
+func (r *results) compareRows() error {
+	r.operation = "rows"
+	ignored, err := r.resolveColumns(ignoreColumns)
+	if err != nil {
+		return err
+	}
+	r.ignored = ignored
+	for _, element := range r.fileSetA.set.Keys() {
+		if !r.fileSetB.set.Contains(element) {
+			r.setAB.Add(element)
+		}
+	}
+	for _, element := range r.fileSetB.set.Keys() {
+		if !r.fileSetA.set.Contains(element) {
+			r.setBA.Add(element)
+		}
+	}
+	for _, element := range r.fileSetA.set.Elements() {
+		if !r.fileSetB.set.Contains(element) {
+			continue
+		}
+		if diffs := diffColumns(r.fileSetA.columns(element), r.fileSetB.columns(element), r.ignored); len(diffs) > 0 {
+			r.changed = append(r.changed, rowChange{key: element, diffs: diffs})
+		}
+	}
+	return nil
+}
+
+/*
+resolveColumns converts a list of 1-based column indexes or header names into a set of remaining column indexes, i.e.
+excluding the key column. Names are looked up in the headers of both files. It returns an error if a column is the key
+column, is not a positive index, or is not found in either header.
+*/
+func (r *results) resolveColumns(columns []string) (map[int]bool, error) {
+	resolved := map[int]bool{}
+	for _, c := range columns {
+		index, err := r.columnIndex(c)
+		if err != nil {
+			return nil, err
+		}
+		resolved[index] = true
+	}
+	return resolved, nil
+}
+
+/*
+columnIndex converts a 1-based column index of the rows of fileA, or a header name, into a remaining column index. The
+header rows list the key first, followed by the remaining columns.
+*/
+func (r *results) columnIndex(column string) (int, error) {
+	n, err := strconv.Atoi(column)
+	if err != nil {
+		for _, header := range [][]string{r.fileSetA.header, r.fileSetB.header} {
+			for i, name := range header {
+				if !strings.EqualFold(name, column) {
+					continue
+				}
+				if i == 0 {
+					return 0, fmt.Errorf("column %s is the key column", column)
+				}
+				return i - 1, nil
+			}
+		}
+		return 0, fmt.Errorf("column not found in header: %s", column)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("invalid column index: %d", n)
+	}
+	index, ok := r.fileSetA.remainingIndex(n)
+	if !ok {
+		return 0, fmt.Errorf("column %s is the key column", column)
+	}
+	return index, nil
+}
+
+/*
+diffColumns returns the columns that differ between a and b, skipping any indexes in ignored. A column missing from one
+row is treated as empty.
+*/
+func diffColumns(a, b []string, ignored map[int]bool) []columnDiff {
+	var diffs []columnDiff
+	for i := 0; i < max(len(a), len(b)); i++ {
+		if ignored[i] {
+			continue
+		}
+		var va, vb string
+		if i < len(a) {
+			va = a[i]
+		}
+		if i < len(b) {
+			vb = b[i]
+		}
+		if !valuesEqual(va, vb) {
+			diffs = append(diffs, columnDiff{index: i, a: va, b: vb})
+		}
+	}
+	return diffs
+}
+
+/*
+valuesEqual reports whether two column values are equal, ignoring case unless caseSensitive is set. If a tolerance flag
+is set and both values are numbers, they are equal if they are within the tolerance.
+*/
+func valuesEqual(a, b string) bool {
+	if tolerance > 0 || tolerancePct > 0 {
+		if _, fa, ok := diffit.CanonicalNumber(a); ok {
+			if _, fb, ok := diffit.CanonicalNumber(b); ok {
+				return withinTolerance(fa, fb)
+			}
+		}
+	}
+	if caseSensitive {
+		return a == b
+	}
+	return foldCase(a) == foldCase(b)
+}
+
+/*
+columnName returns the display name of the remaining column at index. This is the header name if either file has a
+header row, otherwise its 1-based position in the rows of fileA, counting the key at the position of its field.
+*/
+func (r *results) columnName(index int) string {
+	for _, header := range [][]string{r.fileSetA.header, r.fileSetB.header} {
+		if index+1 < len(header) {
+			return header[index+1]
+		}
+	}
+	return fmt.Sprintf("column %d", r.fileSetA.fieldNumber(index))
+}
+
+// printRows prints the removed, added, and changed rows as plain text. Headers are omitted if the pipe flag is set.
+func (r *results) printRows(w io.Writer) error {
+	if !pipe {
+		fmt.Fprintln(w, translate("Removed (only in %s):", r.fileSetA.path))
+	}
+	for _, key := range sortElements(r.setAB, &r.fileSetA, &r.fileSetB) {
+		fmt.Fprintln(w, r.textLine(key, &r.fileSetA, &r.fileSetB))
+	}
+	if !pipe {
+		fmt.Fprintf(w, "\n%s\n", translate("Added (only in %s):", r.fileSetB.path))
+	}
+	for _, key := range sortElements(r.setBA, &r.fileSetB, &r.fileSetA) {
+		fmt.Fprintln(w, r.textLine(key, &r.fileSetB, &r.fileSetA))
+	}
+	if !pipe {
+		fmt.Fprintf(w, "\n%s\n", translate("Changed:"))
+	}
+	for _, c := range r.changed {
+		parts := make([]string, len(c.diffs))
+		for i, d := range c.diffs {
+			parts[i] = fmt.Sprintf("%s: %q -> %q", r.columnName(d.index), d.a, d.b)
+		}
+		fmt.Fprintf(w, "%s: %s\n", r.display(c.key), strings.Join(parts, "; "))
+	}
+	return nil
+}
+
+/*
+writeRowsCSV writes the row classification as CSV with change, key, column, a, and b columns. Changed rows produce one
+record per differing column. Added and removed rows produce a single record holding their remaining columns, joined by
+the delimiter, in the a or b column.
+*/
+func (r *results) writeRowsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(r.rowsRecords()); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// rowsRecords returns the row classification as records with change, key, column, a, and b columns.
+func (r *results) rowsRecords() [][]string {
+	records := [][]string{{"change", "key", "column", "a", "b"}}
+	for _, key := range sortElements(r.setAB, &r.fileSetA, &r.fileSetB) {
+		records = append(records, []string{"removed", r.display(key), "", strings.Join(r.fileSetA.columns(key), delimiter), ""})
+	}
+	for _, key := range sortElements(r.setBA, &r.fileSetB, &r.fileSetA) {
+		records = append(records, []string{"added", r.display(key), "", "", strings.Join(r.fileSetB.columns(key), delimiter)})
+	}
+	for _, c := range r.changed {
+		for _, d := range c.diffs {
+			records = append(records, []string{"changed", r.display(c.key), r.columnName(d.index), d.a, d.b})
+		}
+	}
+	return records
+}
+
+// writeRowsJSON writes the row classification as a single indented JSON document.
+func (r *results) writeRowsJSON(w io.Writer) error {
+	report := jsonRowsReport{
+		Operation: r.operation,
+		FileA:     r.fileSetA.path,
+		FileB:     r.fileSetB.path,
+		Added:     []jsonRow{},
+		Removed:   []jsonRow{},
+		Changed:   []jsonRowChange{},
+	}
+	for _, key := range sortElements(r.setAB, &r.fileSetA, &r.fileSetB) {
+		report.Removed = append(report.Removed, jsonRow{Key: r.display(key), Columns: r.fileSetA.columns(key)})
+	}
+	for _, key := range sortElements(r.setBA, &r.fileSetB, &r.fileSetA) {
+		report.Added = append(report.Added, jsonRow{Key: r.display(key), Columns: r.fileSetB.columns(key)})
+	}
+	for _, c := range r.changed {
+		change := jsonRowChange{Key: r.display(c.key)}
+		for _, d := range c.diffs {
+			change.Differences = append(change.Differences, jsonColumnDiff{Column: r.columnName(d.index), A: d.a, B: d.b})
+		}
+		report.Changed = append(report.Changed, change)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// writeRowsQuickfix writes the removed, added, and changed rows as "file:line: message" entries for editors.
+func (r *results) writeRowsQuickfix(w io.Writer) error {
+	var entries []string
+	for _, key := range sortElements(r.setAB, &r.fileSetA, &r.fileSetB) {
+		path, line := location(key, &r.fileSetA, &r.fileSetB)
+		entries = append(entries, fmt.Sprintf("%s:%d: removed: %s", path, line, r.textLine(key, &r.fileSetA, &r.fileSetB)))
+	}
+	for _, key := range sortElements(r.setBA, &r.fileSetB, &r.fileSetA) {
+		path, line := location(key, &r.fileSetB, &r.fileSetA)
+		entries = append(entries, fmt.Sprintf("%s:%d: added: %s", path, line, r.textLine(key, &r.fileSetB, &r.fileSetA)))
+	}
+	for _, c := range r.changed {
+		path, line := location(c.key, &r.fileSetB, &r.fileSetA)
+		for _, d := range c.diffs {
+			entries = append(entries, fmt.Sprintf("%s:%d: changed: %s: %s: %q -> %q", path, line, r.display(c.key), r.columnName(d.index), d.a, d.b))
+		}
+	}
+	for _, entry := range entries {
+		if _, err := fmt.Fprintln(w, entry); err != nil {
+			return fmt.Errorf("failed to write quickfix: %w", err)
+		}
+	}
+	return nil
+}
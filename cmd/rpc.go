@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+// JSON-RPC 2.0 error codes used by the stdio server.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+/*
+rpcRequest is a JSON-RPC 2.0 request. A valid request without an id is a notification and gets no response, while an
+invalid one is answered with an Invalid Request error and a null id.
+*/
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcError is the error of a failed JSON-RPC request.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+/*
+rpcInput is one side of a comparison. It refers to a set loaded earlier by name, or is read from path, which may be an
+input source, or from content, using parser if set.
+*/
+type rpcInput struct {
+	Name    string `json:"name,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Content string `json:"content,omitempty"`
+	Parser  string `json:"parser,omitempty"`
+}
+
+// rpcCompareParams are the params of the compare and stats methods.
+type rpcCompareParams struct {
+	A         rpcInput `json:"a"`
+	B         rpcInput `json:"b"`
+	Operation string   `json:"operation,omitempty"`
+}
+
+// rpcServer holds the sets loaded by a stdio JSON-RPC session.
+type rpcServer struct {
+	loaded map[string]fileSet
+}
+
+/*
+serveStdioRPC reads JSON-RPC 2.0 requests from r, one per line, and writes a response line to w for each request that
+has an id or is invalid, until r is closed. Sets loaded with the load method stay in memory between requests, so a
+large snapshot only has to be read once. The methods are:
+  - load {name, path | content, parser} reads an input and keeps it as name
+  - unload {name} forgets a loaded input
+  - compare {a, b, operation} returns the JSON report of difference (the default), intersection, or union
+  - stats {a, b} returns the sizes of both inputs and of their differences, intersection, and union
+*/
+func serveStdioRPC(ctx context.Context, r io.Reader, w io.Writer) error {
+	s := &rpcServer{loaded: map[string]fileSet{}}
+	scanner := bufio.NewScanner(contextReader{ctx, r})
+	scanner.Buffer(make([]byte, 64*1024), 256<<20)
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			if err := enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, err.Error()}}); err != nil {
+				return fmt.Errorf("failed to write response: %w", err)
+			}
+			continue
+		}
+		result, rpcErr := s.handle(ctx, req)
+		id := req.ID
+		if id == nil {
+			// only a valid request without an id is a notification, an invalid one is answered with a null id
+			if rpcErr == nil || rpcErr.Code != rpcInvalidRequest {
+				continue
+			}
+			id = json.RawMessage("null")
+		}
+		resp := rpcResponse{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr}
+		if rpcErr == nil && result == nil {
+			resp.Result = struct{}{}
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read requests: %w", err)
+	}
+	return nil
+}
+
+// handle dispatches a request to its method and returns the method's result or error.
+func (s *rpcServer) handle(ctx context.Context, req rpcRequest) (any, *rpcError) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &rpcError{rpcInvalidRequest, "invalid request"}
+	}
+	l.Debug().Str("method", req.Method).Msg("rpc request")
+	switch req.Method {
+	case "load":
+		var params rpcInput
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{rpcInvalidParams, "load requires a name and a path or content"}
+		}
+		name := params.Name
+		params.Name = ""
+		fs, err := s.input(ctx, params, name)
+		if err != nil {
+			return nil, &rpcError{rpcServerError, err.Error()}
+		}
+		s.loaded[name] = fs
+		return map[string]any{"name": name, "size": fs.set.Len()}, nil
+	case "unload":
+		var params rpcInput
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{rpcInvalidParams, "unload requires a name"}
+		}
+		delete(s.loaded, params.Name)
+		return nil, nil
+	case "compare", "stats":
+		var params rpcCompareParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		fsA, err := s.input(ctx, params.A, "a")
+		if err != nil {
+			return nil, &rpcError{rpcServerError, err.Error()}
+		}
+		fsB, err := s.input(ctx, params.B, "b")
+		if err != nil {
+			return nil, &rpcError{rpcServerError, err.Error()}
+		}
+		rs := results{fileSetA: fsA, fileSetB: fsB, setAB: *hashset.New(), setBA: *hashset.New()}
+		if req.Method == "stats" {
+			return rs.stats(), nil
+		}
+		switch params.Operation {
+		case "", "difference":
+			rs.difference()
+		case "intersection":
+			rs.intersection()
+		case "union":
+			rs.union()
+		default:
+			return nil, &rpcError{rpcInvalidParams, "invalid operation: " + params.Operation}
+		}
+		return rs.jsonReport(), nil
+	}
+	return nil, &rpcError{rpcMethodNotFound, "method not found: " + req.Method}
+}
+
+// input returns the set described by in, reading it unless it refers to a loaded set. label names inline content.
+func (s *rpcServer) input(ctx context.Context, in rpcInput, label string) (fileSet, error) {
+	switch {
+	case in.Name != "":
+		fs, ok := s.loaded[in.Name]
+		if !ok {
+			return fs, fmt.Errorf("no input loaded as %s", in.Name)
+		}
+		return fs, nil
+	case in.Path != "":
+		fs := newFileSet(in.Path)
+		return fs, fs.fileToSet(ctx, in.Parser)
+	case in.Content != "":
+		fs := newFileSet(label)
+		return fs, fs.readInput(ctx, strings.NewReader(in.Content), in.Parser)
+	}
+	return fileSet{}, fmt.Errorf("input %s requires a name, path, or content", label)
+}
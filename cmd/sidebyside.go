@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+// sideBySideRow is a line of the side-by-side output: an element of fileA, of fileB, or of both, and its gutter marker.
+type sideBySideRow struct {
+	left   string
+	gutter byte
+	right  string
+}
+
+/*
+writeSideBySide writes the results in two aligned columns, fileA on the left and fileB on the right, like diff -y. The
+gutter between them marks elements only in fileA with "<", elements only in fileB with ">", and pairs that differ, like
+fuzzy matches or changed rows, with "|". Elements in both, listed for an intersection or union, have a blank gutter.
+Rows are sorted by their left element, or their right one if they have none, and the file names head the columns
+unless the pipe flag is set.
+*/
+func (r *results) writeSideBySide(w io.Writer) error {
+	keys := hashset.New()
+	rows := map[string][]sideBySideRow{}
+	add := func(key string, row sideBySideRow) {
+		keys.Add(key)
+		rows[key] = append(rows[key], row)
+	}
+	onlyA := func(element string) {
+		add(element, sideBySideRow{r.textLine(element, &r.fileSetA, &r.fileSetB), '<', ""})
+	}
+	onlyB := func(element string) {
+		add(element, sideBySideRow{"", '>', r.textLine(element, &r.fileSetB, &r.fileSetA)})
+	}
+	switch r.operation {
+	case "difference", "subset", "rows":
+		for _, element := range r.setAB.Values() {
+			onlyA(element.(string))
+		}
+		for _, element := range r.setBA.Values() {
+			onlyB(element.(string))
+		}
+		for _, c := range r.changed {
+			add(c.key, sideBySideRow{r.textLine(c.key, &r.fileSetA, &r.fileSetB), '|', r.textLine(c.key, &r.fileSetB, &r.fileSetA)})
+		}
+	case "superset":
+		for _, element := range r.setAB.Values() {
+			onlyB(element.(string))
+		}
+	case "intersection", "union":
+		for _, value := range r.setAB.Values() {
+			element := value.(string)
+			switch {
+			// fuzzy matches are paired below
+			case r.fuzzyA[element] || r.fuzzyB[element]:
+			case !r.fileSetB.set.Contains(element):
+				onlyA(element)
+			case !r.fileSetA.set.Contains(element):
+				onlyB(element)
+			default:
+				line := r.textLine(element, &r.fileSetA, &r.fileSetB)
+				add(element, sideBySideRow{line, ' ', line})
+			}
+		}
+	}
+	for _, m := range r.matches {
+		add(m.A, sideBySideRow{r.textLine(m.A, &r.fileSetA, &r.fileSetB), '|', r.textLine(m.B, &r.fileSetB, &r.fileSetA)})
+	}
+
+	var ordered []sideBySideRow
+	if !pipe {
+		ordered = append(ordered, sideBySideRow{r.fileSetA.path, ' ', r.fileSetB.path})
+	}
+	for _, key := range sortElements(*keys, &r.fileSetA, &r.fileSetB) {
+		ordered = append(ordered, rows[key]...)
+	}
+	width := 0
+	for _, row := range ordered {
+		width = max(width, displayWidth(row.left))
+	}
+	var b strings.Builder
+	for _, row := range ordered {
+		b.Reset()
+		b.WriteString(row.left)
+		b.WriteString(strings.Repeat(" ", width-displayWidth(row.left)))
+		b.WriteString("  ")
+		b.WriteByte(row.gutter)
+		if row.right != "" {
+			b.WriteString("  ")
+			b.WriteString(row.right)
+		}
+		line := strings.TrimRight(b.String(), " ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write side-by-side output: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// signingKey is the key given with the sign flag, loaded by setSigningKey, or nil if output isn't signed.
+var signingKey ed25519.PrivateKey
+
+/*
+setSigningKey loads the signing key at path, if set, so an unreadable or invalid key is reported before anything is
+written rather than after the output replaced its target. It returns an error if the key can't be loaded.
+*/
+func setSigningKey(path string) error {
+	signingKey = nil
+	if path == "" {
+		return nil
+	}
+	key, err := loadSigningKey(path)
+	if err != nil {
+		return err
+	}
+	signingKey = key
+	return nil
+}
+
+/*
+loadSigningKey reads the Ed25519 private key at path, either PEM-encoded PKCS#8, as written by
+"openssl genpkey -algorithm ed25519", or an unencrypted OpenSSH key, as written by "ssh-keygen -t ed25519".
+It returns an error if the key can't be read or isn't an Ed25519 key.
+*/
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		return key, nil
+	case *ed25519.PrivateKey:
+		return *key, nil
+	}
+	return nil, fmt.Errorf("signing key %s is not an ed25519 key", path)
+}
+
+/*
+signFile writes a detached Ed25519 signature of the file at path to path.sig, as the raw 64 bytes of the signature, so
+it can be verified with "openssl pkeyutl -verify -rawin". The signature covers the file as written, i.e. after any
+encryption. It is signed with signingKey, which must have been loaded with setSigningKey.
+*/
+func signFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s to sign it: %w", path, err)
+	}
+	if err := os.WriteFile(path+".sig", ed25519.Sign(signingKey, data), 0o644); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
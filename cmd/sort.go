@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+/*
+sortElements returns the elements of hs in the output order selected by the sort flag: alphabetical, numeric with
+non-numbers after the numbers, natural with runs of digits compared as numbers, so host2 comes before host10, version
+with semantic versions ordered by precedence, so 1.2.10 comes after 1.2.9 and 2.0.0-rc1 before 2.0.0, or none, the
+order they were first read in, from primary and then secondary.
+*/
+func sortElements(hs hashset.Set, primary, secondary *fileSet) []string {
+	return orderElements(convertToSortedStringSlice(hs), primary, secondary)
+}
+
+// orderElements reorders alphabetically sorted elements in the output order of the sort flag, like sortElements.
+func orderElements(elements []string, primary, secondary *fileSet) []string {
+	switch sortOrder {
+	case "numeric":
+		sort.SliceStable(elements, func(i, j int) bool { return numericLess(elements[i], elements[j]) })
+	case "natural":
+		sort.SliceStable(elements, func(i, j int) bool { return naturalLess(elements[i], elements[j]) })
+	case "version":
+		sort.SliceStable(elements, func(i, j int) bool { return versionLess(elements[i], elements[j]) })
+	case "none":
+		position := func(element string) int {
+			if i, ok := primary.order[element]; ok {
+				return i
+			}
+			return len(primary.order) + secondary.order[element]
+		}
+		sort.SliceStable(elements, func(i, j int) bool { return position(elements[i]) < position(elements[j]) })
+	}
+	return elements
+}
+
+// numericLess orders a before b if it is the smaller number, or a number while b isn't. Non-numbers sort alphabetically.
+func numericLess(a, b string) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil || errB == nil:
+		return errA == nil
+	}
+	return a < b
+}
+
+// naturalLess orders a before b comparing runs of digits by their numeric value, and everything else rune by rune.
+func naturalLess(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	i, j := 0, 0
+	for i < len(ra) && j < len(rb) {
+		if unicode.IsDigit(ra[i]) && unicode.IsDigit(rb[j]) {
+			startA, startB := i, j
+			for i < len(ra) && unicode.IsDigit(ra[i]) {
+				i++
+			}
+			for j < len(rb) && unicode.IsDigit(rb[j]) {
+				j++
+			}
+			// leading zeros don't change the value, and a longer run of significant digits is a larger number
+			x := strings.TrimLeft(string(ra[startA:i]), "0")
+			y := strings.TrimLeft(string(rb[startB:j]), "0")
+			if len(x) != len(y) {
+				return len(x) < len(y)
+			}
+			if x != y {
+				return x < y
+			}
+			continue
+		}
+		if ra[i] != rb[j] {
+			return ra[i] < rb[j]
+		}
+		i++
+		j++
+	}
+	return len(ra)-i < len(rb)-j
+}
+
+// versionPattern matches a semantic version, with an optional leading v and any number of numeric components, capturing
+// the components and the pre-release identifiers. Build metadata is matched but ignored, as it has no precedence.
+var versionPattern = regexp.MustCompile(`^[vV]?(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+/*
+versionLess orders a before b if it is the lower semantic version: numeric components are compared by value, missing
+components count as 0, and a pre-release, like 1.0.0-rc.1, comes before its release. Elements that aren't versions,
+like package-1.2.10, come after the versions and are compared naturally instead.
+*/
+func versionLess(a, b string) bool {
+	va, vb := versionPattern.FindStringSubmatch(a), versionPattern.FindStringSubmatch(b)
+	switch {
+	case va == nil && vb == nil:
+		return naturalLess(a, b)
+	case va == nil || vb == nil:
+		return va != nil
+	}
+	ca, cb := strings.Split(va[1], "."), strings.Split(vb[1], ".")
+	for i := 0; i < max(len(ca), len(cb)); i++ {
+		x, y := "0", "0"
+		if i < len(ca) {
+			x = ca[i]
+		}
+		if i < len(cb) {
+			y = cb[i]
+		}
+		if x != y && !naturalEqual(x, y) {
+			return naturalLess(x, y)
+		}
+	}
+	// a release has higher precedence than its pre-releases
+	switch {
+	case va[2] == vb[2]:
+		return a < b
+	case va[2] == "":
+		return false
+	case vb[2] == "":
+		return true
+	}
+	return preReleaseLess(va[2], vb[2])
+}
+
+// naturalEqual reports whether a and b are the same number, like 1 and 01.
+func naturalEqual(a, b string) bool {
+	return !naturalLess(a, b) && !naturalLess(b, a)
+}
+
+/*
+preReleaseLess orders the pre-release identifiers a before b as semantic versioning does: dot separated identifiers are
+compared in turn, numerically if both are numbers, with numbers before other identifiers, and a shorter list of equal
+identifiers comes first.
+*/
+func preReleaseLess(a, b string) bool {
+	ia, ib := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < min(len(ia), len(ib)); i++ {
+		x, errX := strconv.Atoi(ia[i])
+		y, errY := strconv.Atoi(ib[i])
+		switch {
+		case errX == nil && errY == nil:
+			if x != y {
+				return x < y
+			}
+		case errX == nil || errY == nil:
+			return errX == nil
+		case ia[i] != ib[i]:
+			return ia[i] < ib[i]
+		}
+	}
+	return len(ia) < len(ib)
+}
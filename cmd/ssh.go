@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	registerParser("authorized-keys", "ssh keys keyed by key material, ignoring options and comments", (*fileSet).parseAuthorizedKeys)
+	registerParser("known-hosts", "ssh known hosts as host and key type, keeping the key as a column", (*fileSet).parseKnownHosts)
+	registerDetector("authorized-keys", func(name string, _ *bufio.Reader) bool {
+		return strings.HasPrefix(name, "authorized_keys")
+	}, nil)
+	registerDetector("known-hosts", func(name string, _ *bufio.Reader) bool {
+		return strings.HasPrefix(name, "known_hosts")
+	}, nil)
+}
+
+/*
+parseAuthorizedKeys reads an SSH authorized_keys file from r and adds each key to the set as its type and base64 key
+material, e.g. "ssh-ed25519 AAAAC3Nz...". Options and comments are ignored when comparing, but the comment and options
+are kept as the element's columns. Blank lines and comment lines are skipped.
+*/
+func (fs *fileSet) parseAuthorizedKeys(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		fs.line++
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyType, key, comment, options, ok := parseAuthorizedKey(line)
+		if !ok {
+			l.Warn().Str("path", fs.path).Int("line", n).Msg("skipping line without a recognized ssh key")
+			continue
+		}
+		fs.addExact(keyType+" "+key, []string{comment, options})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+/*
+parseAuthorizedKey splits an authorized_keys line into its key type, base64 key material, comment, and options. Options
+precede the key type and may contain quoted spaces. It returns false if the line doesn't contain a known key type
+followed by key material.
+*/
+func parseAuthorizedKey(line string) (keyType, key, comment, options string, ok bool) {
+	fields := splitQuoted(line)
+	for i, field := range fields {
+		if !isSSHKeyType(field) || i+1 >= len(fields) {
+			continue
+		}
+		return field, fields[i+1], strings.Join(fields[i+2:], " "), strings.Join(fields[:i], " "), true
+	}
+	return "", "", "", "", false
+}
+
+// isSSHKeyType reports whether s is an SSH public key algorithm name, including certificate and security key types.
+func isSSHKeyType(s string) bool {
+	switch {
+	case s == "ssh-rsa", s == "ssh-dss", s == "ssh-ed25519", s == "ssh-ed448":
+		return true
+	case strings.HasPrefix(s, "ecdsa-sha2-"), strings.HasPrefix(s, "sk-"):
+		return true
+	case strings.HasSuffix(s, "-cert-v01@openssh.com"):
+		return true
+	}
+	return false
+}
+
+// splitQuoted splits s on unquoted whitespace, keeping double quoted sections, like option values, within one field.
+func splitQuoted(s string) []string {
+	var fields []string
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && quoted && i+1 < len(s):
+			b.WriteByte(c)
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			quoted = !quoted
+			b.WriteByte(c)
+		case (c == ' ' || c == '\t') && !quoted:
+			if b.Len() > 0 {
+				fields = append(fields, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if b.Len() > 0 {
+		fields = append(fields, b.String())
+	}
+	return fields
+}
+
+/*
+parseKnownHosts reads an SSH known_hosts file from r and adds each host and key type to the set, e.g. "github.com
+ssh-ed25519", keeping the key as the element's column. Lines listing several hosts produce one element per host. Markers
+like @revoked or @cert-authority are kept at the start of the element, e.g. "@revoked github.com ssh-rsa", so a revoked
+key never matches a trusted one. Blank lines and comments are skipped. Hashed hosts are compared as is.
+*/
+func (fs *fileSet) parseKnownHosts(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		fs.line++
+		n++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		marker := ""
+		if strings.HasPrefix(fields[0], "@") {
+			marker, fields = fields[0]+" ", fields[1:]
+		}
+		if len(fields) < 3 || !isSSHKeyType(fields[1]) {
+			l.Warn().Str("path", fs.path).Int("line", n).Msg("skipping invalid known_hosts entry")
+			continue
+		}
+		for _, host := range strings.Split(fields[0], ",") {
+			fs.add(marker+host+" "+fields[1], []string{fields[2]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+/*
+setStats are the sizes of two sets and of their differences, intersection, and union, along with similarity metrics
+between 0 and 1 derived from them: the Jaccard index (intersection over union), the Sørensen–Dice coefficient (twice the
+intersection over the sum of the sizes), and the overlap coefficient (intersection over the size of the smaller set).
+Sizes count unique elements, and with --multiset, the totals count every line, including duplicates.
+*/
+type setStats struct {
+	SizeA   int     `json:"sizeA"`
+	SizeB   int     `json:"sizeB"`
+	TotalA  int     `json:"totalA,omitempty"`
+	TotalB  int     `json:"totalB,omitempty"`
+	OnlyA   int     `json:"onlyA"`
+	OnlyB   int     `json:"onlyB"`
+	Both    int     `json:"both"`
+	Union   int     `json:"union"`
+	Jaccard float64 `json:"jaccard"`
+	Dice    float64 `json:"dice"`
+	Overlap float64 `json:"overlap"`
+}
+
+// stats counts the elements of both file sets and of their differences, intersection, and union, and their similarity.
+func (r *results) stats() setStats {
+	s := setStats{SizeA: r.fileSetA.set.Len(), SizeB: r.fileSetB.set.Len()}
+	if multiset {
+		s.TotalA, s.TotalB = r.fileSetA.total(), r.fileSetB.total()
+	}
+	for _, element := range r.fileSetA.set.Keys() {
+		if r.fileSetB.set.Contains(element) {
+			s.Both++
+		}
+	}
+	s.OnlyA = s.SizeA - s.Both
+	s.OnlyB = s.SizeB - s.Both
+	s.Union = s.OnlyA + s.OnlyB + s.Both
+	s.Jaccard = ratio(s.Both, s.Union)
+	s.Dice = ratio(2*s.Both, s.SizeA+s.SizeB)
+	s.Overlap = ratio(s.Both, min(s.SizeA, s.SizeB))
+	return s
+}
+
+// ratio returns n divided by d. Two empty sets are identical, so it is 1 if both are 0, and 0 if only d is.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		if n == 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// valueStats summarizes a list of numbers.
+type valueStats struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+}
+
+// summarize returns the count, minimum, maximum, mean, and percentiles of values. All are 0 if values is empty.
+func summarize(values []float64) valueStats {
+	if len(values) == 0 {
+		return valueStats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	return valueStats{
+		Count: len(sorted),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		Mean:  sum / float64(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P99:   percentile(sorted, 99),
+	}
+}
+
+// percentile returns the pth percentile of sorted, interpolating linearly between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// totals returns the summed values of each element in the set of fs.
+func (fs *fileSet) totals() []float64 {
+	values := make([]float64, 0, fs.set.Len())
+	for _, element := range fs.set.Keys() {
+		values = append(values, fs.sums[element])
+	}
+	return values
+}
+
+// deltaValues returns the deltas of the keys whose --value-column totals differ.
+func (r *results) deltaValues() []float64 {
+	deltas := make([]float64, len(r.deltas))
+	for i, d := range r.deltas {
+		deltas[i] = d.delta()
+	}
+	return deltas
+}
+
+// formatStat formats a summary statistic rounded to at most four decimals.
+func formatStat(f float64) string {
+	return formatValue(math.Round(f*1e4) / 1e4)
+}
+
+// String formats the summary on a single line.
+func (s valueStats) String() string {
+	return fmt.Sprintf("n=%d min=%s max=%s mean=%s p50=%s p90=%s p99=%s", s.Count, formatStat(s.Min), formatStat(s.Max),
+		formatStat(s.Mean), formatStat(s.P50), formatStat(s.P90), formatStat(s.P99))
+}
+
+/*
+summaryCounts returns the number of elements only in fileA, only in fileB, and in both, as in the final results rather
+than the sets read, so elements matched by --fuzzy count as in both. In pipe mode, a difference leaves B - A out of the
+results, so it is counted from the sets instead. With --multiset, the counts of the sets are returned.
+*/
+func (r *results) summaryCounts() (onlyA, onlyB, both int) {
+	s := r.stats()
+	if multiset {
+		return s.OnlyA, s.OnlyB, s.Both
+	}
+	switch r.operation {
+	case "difference":
+		onlyA, onlyB = r.setAB.Size(), r.setBA.Size()
+		if pipe {
+			onlyB = s.OnlyB - len(r.fuzzyB)
+		}
+		return onlyA, onlyB, s.SizeA - onlyA
+	case "intersection":
+		both = r.setAB.Size()
+		return s.SizeA - both, s.OnlyB - len(r.fuzzyB), both
+	}
+	return s.OnlyA, s.OnlyB, s.Both
+}
+
+// writeSummary writes a one line recap of the results, like "12 only in a.txt, 4 only in b.txt, 230 in both".
+func (r *results) writeSummary(w io.Writer) error {
+	onlyA, onlyB, both := r.summaryCounts()
+	if !pipe {
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintln(w, translate("%d only in %s, %d only in %s, %d in both", onlyA, r.fileSetA.path, onlyB,
+		r.fileSetB.path, both))
+	if err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+// writeStats writes the stats of the results in the format given by the format flag: plain text, JSON, or CSV.
+func (r *results) writeStats(w io.Writer) error {
+	switch format {
+	case "json":
+		return r.writeStatsJSON(w)
+	case "csv":
+		return r.writeStatsCSV(w)
+	default:
+		return r.printStats(w)
+	}
+}
+
+/*
+printStats prints the sizes of both files and of their differences, intersection, and union as plain text, along with
+the total lines of both files if multiset is set. If valueColumn is set, it also summarizes the totals of each file and
+the deltas of the keys whose totals differ. If groupByDomain is set, a table of the counts of each domain follows.
+*/
+func (r *results) printStats(w io.Writer) error {
+	s := r.stats()
+	lines := []string{
+		fmt.Sprintf("%s: %d", translate("Size of %s", r.fileSetA.path), s.SizeA),
+		fmt.Sprintf("%s: %d", translate("Size of %s", r.fileSetB.path), s.SizeB),
+	}
+	if multiset {
+		lines = append(lines,
+			fmt.Sprintf("%s: %d", translate("Total lines of %s", r.fileSetA.path), s.TotalA),
+			fmt.Sprintf("%s: %d", translate("Total lines of %s", r.fileSetB.path), s.TotalB),
+		)
+	}
+	lines = append(lines,
+		fmt.Sprintf("%s: %d", translate("Only in %s", r.fileSetA.path), s.OnlyA),
+		fmt.Sprintf("%s: %d", translate("Only in %s", r.fileSetB.path), s.OnlyB),
+		fmt.Sprintf("%s: %d", translate("In both"), s.Both),
+		fmt.Sprintf("%s: %d", translate("Union"), s.Union),
+		fmt.Sprintf("%s: %.4f", translate("Jaccard index"), s.Jaccard),
+		fmt.Sprintf("%s: %.4f", translate("Sørensen–Dice coefficient"), s.Dice),
+		fmt.Sprintf("%s: %.4f", translate("Overlap coefficient"), s.Overlap),
+	)
+	if valueColumn != "" {
+		lines = append(lines,
+			"",
+			translate("Totals of %s:", r.valueColumnName()),
+			fmt.Sprintf("%s: %s", r.fileSetA.path, summarize(r.fileSetA.totals())),
+			fmt.Sprintf("%s: %s", r.fileSetB.path, summarize(r.fileSetB.totals())),
+			fmt.Sprintf("%s: %s", translate("Deltas of differing keys"), summarize(r.deltaValues())),
+		)
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write stats: %w", err)
+		}
+	}
+	if groupByDomain {
+		fmt.Fprintln(w)
+		return writeTable(w, r.domainRecords())
+	}
+	return nil
+}
+
+// statsReport is the document written by --stats with the JSON and CSV formats.
+type statsReport struct {
+	FileA string `json:"fileA"`
+	FileB string `json:"fileB"`
+	setStats
+	PercentOnlyA float64        `json:"percentOnlyA"`
+	PercentOnlyB float64        `json:"percentOnlyB"`
+	PercentBothA float64        `json:"percentBothA"`
+	PercentBothB float64        `json:"percentBothB"`
+	Values       *valuesSummary `json:"values,omitempty"`
+	Domains      []domainStats  `json:"domains,omitempty"`
+}
+
+// valuesSummary summarizes the totals of each file and the deltas of the differing keys of --value-column.
+type valuesSummary struct {
+	Column  string     `json:"column"`
+	TotalsA valueStats `json:"totalsA"`
+	TotalsB valueStats `json:"totalsB"`
+	Deltas  valueStats `json:"deltas"`
+}
+
+/*
+statsReport returns the stats of the results with the share of each file only in it and in both as percentages, for
+dashboards, along with the summary of the totals and deltas if valueColumn is set, and the counts of each domain if
+groupByDomain is set.
+*/
+func (r *results) statsReport() statsReport {
+	s := r.stats()
+	report := statsReport{
+		FileA:        r.fileSetA.path,
+		FileB:        r.fileSetB.path,
+		setStats:     s,
+		PercentOnlyA: percent(s.OnlyA, s.SizeA),
+		PercentOnlyB: percent(s.OnlyB, s.SizeB),
+		PercentBothA: percent(s.Both, s.SizeA),
+		PercentBothB: percent(s.Both, s.SizeB),
+	}
+	if valueColumn != "" {
+		report.Values = &valuesSummary{
+			Column:  r.valueColumnName(),
+			TotalsA: summarize(r.fileSetA.totals()),
+			TotalsB: summarize(r.fileSetB.totals()),
+			Deltas:  summarize(r.deltaValues()),
+		}
+	}
+	if groupByDomain {
+		report.Domains = r.domainStats()
+	}
+	return report
+}
+
+// percent returns n as a percentage of d, rounded to two decimals, or 0 if d is 0.
+func percent(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(d)*1e4) / 100
+}
+
+// writeStatsJSON writes the stats of the results as a JSON document.
+func (r *results) writeStatsJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.statsReport()); err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
+
+/*
+writeStatsCSV writes the stats of the results as CSV with a metric and value column, one record per field of the JSON
+document. The summaries of --value-column are flattened into metrics like totalsA.p50, and the counts of
+--group-by-domain into metrics like domains[corp.net].onlyA.
+*/
+func (r *results) writeStatsCSV(w io.Writer) error {
+	report := r.statsReport()
+	s := report.setStats
+	float := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	records := [][]string{
+		{"metric", "value"},
+		{"fileA", report.FileA},
+		{"fileB", report.FileB},
+		{"sizeA", strconv.Itoa(s.SizeA)},
+		{"sizeB", strconv.Itoa(s.SizeB)},
+	}
+	if multiset {
+		records = append(records, []string{"totalA", strconv.Itoa(s.TotalA)}, []string{"totalB", strconv.Itoa(s.TotalB)})
+	}
+	records = append(records,
+		[]string{"onlyA", strconv.Itoa(s.OnlyA)},
+		[]string{"onlyB", strconv.Itoa(s.OnlyB)},
+		[]string{"both", strconv.Itoa(s.Both)},
+		[]string{"union", strconv.Itoa(s.Union)},
+		[]string{"jaccard", float(s.Jaccard)},
+		[]string{"dice", float(s.Dice)},
+		[]string{"overlap", float(s.Overlap)},
+		[]string{"percentOnlyA", float(report.PercentOnlyA)},
+		[]string{"percentOnlyB", float(report.PercentOnlyB)},
+		[]string{"percentBothA", float(report.PercentBothA)},
+		[]string{"percentBothB", float(report.PercentBothB)},
+	)
+	if v := report.Values; v != nil {
+		records = append(records, []string{"values.column", v.Column})
+		for _, summary := range []struct {
+			name  string
+			stats valueStats
+		}{{"totalsA", v.TotalsA}, {"totalsB", v.TotalsB}, {"deltas", v.Deltas}} {
+			st := summary.stats
+			records = append(records,
+				[]string{summary.name + ".count", strconv.Itoa(st.Count)},
+				[]string{summary.name + ".min", float(st.Min)},
+				[]string{summary.name + ".max", float(st.Max)},
+				[]string{summary.name + ".mean", float(st.Mean)},
+				[]string{summary.name + ".p50", float(st.P50)},
+				[]string{summary.name + ".p90", float(st.P90)},
+				[]string{summary.name + ".p99", float(st.P99)},
+			)
+		}
+	}
+	if groupByDomain {
+		records = append(records, r.domainMetrics()...)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
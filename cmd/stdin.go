@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinPath is the path of an input read from stdin.
+const stdinPath = "-"
+
+// openStdin returns stdin as an input, decompressing it if it is compressed.
+func openStdin() (io.ReadCloser, error) {
+	return decompress(io.NopCloser(os.Stdin), stdinPath)
+}
+
+/*
+splitStdin reads stdin once and splits it at the first line equal to marker, reading the lines before it into fsA and
+the lines after it into fsB, so both inputs can be given in a single pipe or heredoc. Trailing carriage returns are
+ignored when matching the marker. It returns an error if the marker is not found.
+*/
+func splitStdin(ctx context.Context, marker string, fsA, fsB *fileSet) error {
+	in, err := openStdin()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	var a, b bytes.Buffer
+	half := &a
+	scanner := bufio.NewScanner(contextReader{ctx, in})
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if half == &a && strings.TrimSuffix(line, "\r") == marker {
+			half = &b
+			continue
+		}
+		half.WriteString(line)
+		half.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+	if half == &a {
+		return fmt.Errorf("separator %q not found in stdin", marker)
+	}
+	if err := fsA.readInput(ctx, &a, parserName); err != nil {
+		return err
+	}
+	return fsB.readInput(ctx, &b, parserName)
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alexandrestein/gods/sets/hashset"
+)
+
+// streamChunkSize is the number of elements held in memory per input by --streaming before they are sorted and spilled
+// to a temporary run file.
+const streamChunkSize = 1 << 20
+
+// streamRuns are the sorted run files an input has been spilled to by --streaming.
+type streamRuns struct {
+	dir   string
+	paths []string
+	err   error // the first error spilling a run, since elements are added without returning errors
+}
+
+/*
+spill writes the elements of fs, sorted, to a new run file and resets fs, so its memory is reused for the next chunk of
+the input. The header, line number, lines to skip, selected column, and prefilter are kept, since they describe the
+input rather than the chunk.
+*/
+func (fs *fileSet) spill() {
+	if fs.runs.err != nil || fs.set.Len() == 0 {
+		return
+	}
+	file, err := os.CreateTemp(fs.runs.dir, "run-*")
+	if err != nil {
+		fs.runs.err = fmt.Errorf("failed to create run file: %w", err)
+		return
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	for _, element := range fs.set.Elements() {
+		w.WriteString(element)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		fs.runs.err = fmt.Errorf("failed to write run file: %w", err)
+		return
+	}
+	fs.runs.paths = append(fs.runs.paths, file.Name())
+
+	fresh := newFileSet(fs.path)
+	fresh.header, fresh.line, fresh.skip, fresh.column = fs.header, fs.line, fs.skip, fs.column
+	fresh.runs, fresh.prefilter = fs.runs, fs.prefilter
+	*fs = fresh
+}
+
+/*
+streamToRuns reads the input at fs.path into sorted run files in dir, holding at most streamChunkSize elements in
+memory at a time. Returns an error if the input can't be read or streamed, or if ctx is canceled.
+*/
+func (fs *fileSet) streamToRuns(ctx context.Context, dir string) error {
+	fs.runs = &streamRuns{dir: dir}
+	if err := fs.readStream(ctx); err != nil {
+		return err
+	}
+	fs.spill()
+	return fs.runs.err
+}
+
+/*
+readStream adds the elements of the input at fs.path to the set, spilling them to run files if fs.runs is set. Only
+line-based inputs, plain text and CSV, can be streamed. Returns an error if the input can't be read or is in another
+format, or if ctx is canceled.
+*/
+func (fs *fileSet) readStream(ctx context.Context) (err error) {
+	file, err := openInput(ctx, fs.path)
+	if err != nil {
+		return err
+	}
+	defer func() { closeInput(file, err) }()
+	r, finish := fs.withProgress(file)
+	defer finish()
+	br, err := fs.checkBinary(bufio.NewReader(contextReader{ctx, r}))
+	if err != nil || br == nil {
+		return err
+	}
+	name := inputFormat
+	if name == "auto" {
+		name = detectFormat(fs.path, br)
+	}
+	if name != "plain" && name != "csv" {
+		return fmt.Errorf("%s is %s, --streaming only supports plain and csv inputs", fs.path, name)
+	}
+	return fs.scanLines(br)
+}
+
+// runReader is the next element of an open run file.
+type runReader struct {
+	scanner *bufio.Scanner
+	element string
+}
+
+// runHeap orders run readers by their next element, so the smallest element of all runs is always on top.
+type runHeap []*runReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].element < h[j].element }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// runMerger merges sorted run files into a single sorted stream of unique elements.
+type runMerger struct {
+	files   []*os.File
+	heap    runHeap
+	last    string
+	started bool
+	err     error
+}
+
+// newRunMerger opens the run files at paths for merging. The merger must be closed to close them.
+func newRunMerger(paths []string) (*runMerger, error) {
+	m := &runMerger{}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("failed to open run file: %w", err)
+		}
+		m.files = append(m.files, file)
+		r := &runReader{scanner: bufio.NewScanner(file)}
+		if r.scanner.Scan() {
+			r.element = r.scanner.Text()
+			m.heap = append(m.heap, r)
+		} else if err := r.scanner.Err(); err != nil {
+			m.Close()
+			return nil, fmt.Errorf("failed to read run file: %w", err)
+		}
+	}
+	heap.Init(&m.heap)
+	return m, nil
+}
+
+// next returns the next unique element in sorted order, or false once all runs are exhausted or reading one fails.
+func (m *runMerger) next() (string, bool) {
+	for m.heap.Len() > 0 && m.err == nil {
+		r := m.heap[0]
+		element := r.element
+		if r.scanner.Scan() {
+			r.element = r.scanner.Text()
+			heap.Fix(&m.heap, 0)
+		} else {
+			if err := r.scanner.Err(); err != nil {
+				m.err = fmt.Errorf("failed to read run file: %w", err)
+			}
+			heap.Pop(&m.heap)
+		}
+		// the same element can be in several runs, but is only returned once
+		if m.started && element == m.last {
+			continue
+		}
+		m.last, m.started = element, true
+		return element, true
+	}
+	return "", false
+}
+
+// Close closes the run files.
+func (m *runMerger) Close() {
+	for _, file := range m.files {
+		file.Close()
+	}
+}
+
+/*
+streamComparison compares the inputs named by args with --streaming: both are spilled to sorted run files in a
+temporary directory, which are merged and joined in a single pass, so memory stays bounded however large the inputs
+are. If one input is much smaller than the other, or bloom is set, the larger one is prefiltered against the smaller
+one by streamPrefiltered instead. The difference, intersection, or union is written in the plain text format as it is
+found. The second half of a difference is buffered to another temporary file, since it is printed after the first.
+*/
+func streamComparison(ctx context.Context, operation string, args []string) error {
+	dir, err := os.MkdirTemp("", "godiffit-stream-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fsA := newFileSet(args[0])
+	fsA.skip = skippedLines(0)
+	fsB := newFileSet(args[1])
+	fsB.skip = skippedLines(1)
+	sets := []*fileSet{&fsA, &fsB}
+	runDirs := make([]string, len(sets))
+	for i := range sets {
+		runDirs[i] = filepath.Join(dir, fmt.Sprint("runs-", i))
+		if err := os.Mkdir(runDirs[i], 0o700); err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+	}
+	if small, ok := smallerInput(args); ok {
+		large := 1 - small
+		if err := streamPrefiltered(ctx, sets[small], sets[large], runDirs[small], runDirs[large]); err != nil {
+			exitIfInterrupted(ctx)
+			return err
+		}
+	} else {
+		for i, fs := range sets {
+			if err := fs.streamToRuns(ctx, runDirs[i]); err != nil {
+				exitIfInterrupted(ctx)
+				return err
+			}
+		}
+	}
+	mergeA, err := newRunMerger(fsA.runs.paths)
+	if err != nil {
+		return err
+	}
+	defer mergeA.Close()
+	mergeB, err := newRunMerger(fsB.runs.paths)
+	if err != nil {
+		return err
+	}
+	defer mergeB.Close()
+
+	out, err := createOutput(ctx, outputPath)
+	if err != nil {
+		return err
+	}
+	r := results{fileSetA: fsA, fileSetB: fsB, operation: operation, setAB: *hashset.New(), setBA: *hashset.New()}
+	if err := r.writeJoin(out, mergeA, mergeB, dir); err != nil {
+		out.Abort()
+		exitIfInterrupted(ctx)
+		return err
+	}
+	return out.Close()
+}
+
+/*
+writeJoin merge-joins the sorted unique elements of a and b, writing the result of r.operation to w with the headers of
+printSet. Elements only in b are written to a file in dir while joining, and copied to w afterwards.
+*/
+func (r *results) writeJoin(w io.Writer, a, b *runMerger, dir string) error {
+	bw := bufio.NewWriter(w)
+	if !pipe {
+		header, err := r.header()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(bw, header)
+	}
+	var onlyB *bufio.Writer
+	if r.operation == "difference" && !pipe {
+		file, err := os.Create(filepath.Join(dir, "only-b"))
+		if err != nil {
+			return fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		defer file.Close()
+		onlyB = bufio.NewWriter(file)
+	}
+
+	x, okA := a.next()
+	y, okB := b.next()
+	for okA || okB {
+		switch {
+		case okA && (!okB || x < y):
+			if r.operation != "intersection" {
+				fmt.Fprintln(bw, x)
+			}
+			x, okA = a.next()
+		case okB && (!okA || y < x):
+			if r.operation == "union" {
+				fmt.Fprintln(bw, y)
+			} else if onlyB != nil {
+				fmt.Fprintln(onlyB, y)
+			}
+			y, okB = b.next()
+		default:
+			if r.operation != "difference" {
+				fmt.Fprintln(bw, x)
+			}
+			x, okA = a.next()
+			y, okB = b.next()
+		}
+	}
+	if a.err != nil {
+		return a.err
+	}
+	if b.err != nil {
+		return b.err
+	}
+
+	if onlyB != nil {
+		if err := onlyB.Flush(); err != nil {
+			return fmt.Errorf("failed to write temporary file: %w", err)
+		}
+		fmt.Fprintf(bw, "\n%s\n", translate("Difference of %s - %s:", r.fileSetB.path, r.fileSetA.path))
+		file, err := os.Open(filepath.Join(dir, "only-b"))
+		if err != nil {
+			return fmt.Errorf("failed to read temporary file: %w", err)
+		}
+		defer file.Close()
+		if _, err := io.Copy(bw, file); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import "fmt"
+
+// suggestion is the closest element of the other file to an element only in one file, found by --suggest.
+type suggestion struct {
+	Closest  string
+	Distance int
+}
+
+/*
+suggestClosest finds, for each element of the difference result sets, the closest element only in the other file within
+suggestDist edits, preferring the lexicographically smallest on ties, so typo-driven drift, like host01 in one file and
+host-01 in the other, is obvious. The suggestions are stored in r.suggestions, keyed by element. Intersections and
+unions have nothing to suggest.
+*/
+func (r *results) suggestClosest() {
+	r.suggestions = map[string]suggestion{}
+	for _, rs := range r.resultSets() {
+		if rs.name != "A-B" && rs.name != "B-A" {
+			continue
+		}
+		candidates := onlyIn(rs.secondary, rs.primary)
+		for _, element := range rs.elements {
+			if closest, d, ok := closest(element, candidates, suggestDist); ok {
+				r.suggestions[element] = suggestion{closest, d}
+			}
+		}
+	}
+}
+
+// onlyIn returns the elements of fs that other doesn't contain, sorted.
+func onlyIn(fs, other *fileSet) []string {
+	var elements []string
+	for _, element := range fs.set.Elements() {
+		if !other.set.Contains(element) {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// suggestionColumns returns the closest element and distance suggested for element as columns, or nil unless
+// suggestDist is set. Elements without a suggestion have empty columns.
+func (r *results) suggestionColumns(element string) []string {
+	if r.suggestions == nil {
+		return nil
+	}
+	s, ok := r.suggestions[element]
+	if !ok {
+		return []string{"", ""}
+	}
+	return []string{r.display(s.Closest), fmt.Sprint(s.Distance)}
+}
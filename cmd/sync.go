@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonSyncReport is the document written by the JSON output format when suggesting a sync.
+type jsonSyncReport struct {
+	FileA  string   `json:"fileA"`
+	FileB  string   `json:"fileB"`
+	AddToA []string `json:"addToA"`
+	AddToB []string `json:"addToB"`
+}
+
+// keepRawLines reports whether the unnormalized lines of elements must be kept, to write them to another file.
+func keepRawLines() bool {
+	return emitPatch != "" || suggestSync || interactive
+}
+
+/*
+rawLine returns element as first read, before normalization, joined with its remaining columns, back at the position
+of the field it was read from.
+*/
+func (fs *fileSet) rawLine(element string) string {
+	line := fs.raw[element]
+	if line == "" {
+		line = element
+	}
+	columns := fs.rows[element]
+	if len(columns) == 0 {
+		return line
+	}
+	i := min(fs.selectedField()-1, len(columns))
+	fields := append(append(append([]string{}, columns[:i]...), line), columns[i:]...)
+	return strings.Join(fields, delimiter)
+}
+
+/*
+suggestSync finds the lines to add to each file to make their sets equal, for reconciling two sources when neither is
+authoritative. Elements only in fileSetB must be added to fileA and are stored in setBA, while elements only in fileSetA
+must be added to fileB and are stored in setAB.
+*/
+func (r *results) suggestSync() {
+	r.operation = "sync"
+	for _, element := range r.fileSetA.set.Keys() {
+		if !r.fileSetB.set.Contains(element) {
+			r.setAB.Add(element)
+		}
+	}
+	for _, element := range r.fileSetB.set.Keys() {
+		if !r.fileSetA.set.Contains(element) {
+			r.setBA.Add(element)
+		}
+	}
+}
+
+// syncLines returns the lines to add to fileA and to fileB, as first read from the other file.
+func (r *results) syncLines() (addToA, addToB []string) {
+	addToA, addToB = []string{}, []string{}
+	for _, element := range sortElements(r.setBA, &r.fileSetB, &r.fileSetA) {
+		addToA = append(addToA, r.fileSetB.rawLine(element))
+	}
+	for _, element := range sortElements(r.setAB, &r.fileSetA, &r.fileSetB) {
+		addToB = append(addToB, r.fileSetA.rawLine(element))
+	}
+	return addToA, addToB
+}
+
+// printSync prints the lines to add to each file under a heading naming the file. Headings are omitted if pipe is set.
+func (r *results) printSync(w io.Writer) error {
+	addToA, addToB := r.syncLines()
+	for i, group := range []struct {
+		path  string
+		lines []string
+	}{{r.fileSetA.path, addToA}, {r.fileSetB.path, addToB}} {
+		if !pipe {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s (%d):\n", translate("Add to %s", group.path), len(group.lines))
+		}
+		for _, line := range group.lines {
+			fmt.Fprintln(w, line)
+		}
+	}
+	return nil
+}
+
+// writeSyncCSV writes the lines to add as CSV with a file and line column.
+func (r *results) writeSyncCSV(w io.Writer) error {
+	addToA, addToB := r.syncLines()
+	records := [][]string{{"file", "line"}}
+	for _, line := range addToA {
+		records = append(records, []string{r.fileSetA.path, line})
+	}
+	for _, line := range addToB {
+		records = append(records, []string{r.fileSetB.path, line})
+	}
+	if err := csv.NewWriter(w).WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// writeSyncJSON writes the lines to add to each file as a single indented JSON document.
+func (r *results) writeSyncJSON(w io.Writer) error {
+	report := jsonSyncReport{FileA: r.fileSetA.path, FileB: r.fileSetB.path}
+	report.AddToA, report.AddToB = r.syncLines()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerParser("syslog", "syslog files as \"program: message\", keeping the timestamp and host as columns", (*fileSet).parseSyslog)
+	registerDetector("syslog", nil, func(head []byte) bool {
+		line, _, _ := bytes.Cut(head, []byte("\n"))
+		return bsdSyslogPattern.Match(line) || isoSyslogPattern.Match(line) || ietfSyslogPattern.Match(line)
+	})
+}
+
+var (
+	// bsdSyslogPattern matches an RFC 3164 syslog line, e.g. "May  1 12:00:00 host sshd[42]: message".
+	bsdSyslogPattern = regexp.MustCompile(`^(?:<\d+>)?([A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d) (\S+) (.*)$`)
+	// isoSyslogPattern matches a syslog line with an RFC 3339 timestamp, as written by rsyslog's high precision format.
+	isoSyslogPattern = regexp.MustCompile(`^(?:<\d+>)?(\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\S*) (\S+) (.*)$`)
+	// ietfSyslogPattern matches an RFC 5424 syslog line, capturing the timestamp, host, app name, and message.
+	ietfSyslogPattern = regexp.MustCompile(`^<\d+>1 (\S+) (\S+) (\S+) \S+ \S+ (?:-|(?:\[(?:[^\]\\]|\\.)*\])+) ?(.*)$`)
+	// syslogTagPattern matches the "program[pid]: " tag that starts the message of an RFC 3164 line.
+	syslogTagPattern = regexp.MustCompile(`^([^\s:\[]+)(?:\[\d+\])?: ?(.*)$`)
+)
+
+/*
+parseSyslog reads syslog lines from r in RFC 3164, RFC 5424, or RFC 3339 timestamped format and adds each to the set as
+"program: message", dropping the timestamp, host, and process ID so the same events logged at different times or on
+different hosts are equal. The timestamp, converted to RFC 3339, and the host are kept as columns, the second and third
+fields of the row, so the date-range flag can select a time window with --date-field 2 --date-format rfc3339. Lines that
+aren't syslog, like continuations of a multi-line message, are skipped.
+*/
+func (fs *fileSet) parseSyslog(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fs.line++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		var timestamp, host, program, message string
+		if m := ietfSyslogPattern.FindStringSubmatch(line); m != nil {
+			timestamp, host, program, message = m[1], m[2], m[3], strings.TrimPrefix(m[4], "\ufeff")
+			if program == "-" {
+				program = ""
+			}
+		} else {
+			m := bsdSyslogPattern.FindStringSubmatch(line)
+			if m == nil {
+				m = isoSyslogPattern.FindStringSubmatch(line)
+			}
+			if m == nil {
+				l.Debug().Str("path", fs.path).Str("line", line).Msg("skipping unrecognized syslog line")
+				continue
+			}
+			timestamp, host, message = m[1], m[2], m[3]
+			if tag := syslogTagPattern.FindStringSubmatch(message); tag != nil {
+				program, message = tag[1], tag[2]
+			}
+		}
+		element := message
+		if program != "" {
+			element = program + ": " + message
+		}
+		fs.add(element, []string{syslogTime(timestamp), host})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+/*
+syslogTime converts a syslog timestamp to RFC 3339. RFC 3164 timestamps have no year, so the current year is assumed,
+or the previous one if that would put the timestamp in the future. Timestamps that can't be parsed are returned as is.
+*/
+func syslogTime(timestamp string) string {
+	if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	t, err := time.ParseInLocation(time.Stamp, timestamp, time.Local)
+	if err != nil {
+		return timestamp
+	}
+	now := time.Now()
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t.Format(time.RFC3339)
+}
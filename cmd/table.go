@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+/*
+displayWidth returns the number of terminal columns s occupies. East Asian wide and fullwidth characters, like CJK
+ideographs, occupy two columns, and combining marks and format characters, like zero width joiners, occupy none.
+*/
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		case width.LookupRune(r).Kind() == width.EastAsianWide, width.LookupRune(r).Kind() == width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+/*
+writeTable writes records as a table of left aligned columns separated by two spaces, padding each cell to the display
+width of the widest cell in its column, so columns holding CJK text line up in a terminal. The first record is the
+header. Records may have different numbers of columns, and the last cell of each record is not padded.
+*/
+func writeTable(w io.Writer, records [][]string) error {
+	var widths []int
+	for _, record := range records {
+		for i, cell := range record {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+	var b strings.Builder
+	for _, record := range records {
+		b.Reset()
+		for i, cell := range record {
+			b.WriteString(cell)
+			if i < len(record)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		b.WriteByte('\n')
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return fmt.Errorf("failed to write table: %w", err)
+		}
+	}
+	return nil
+}
+
+// markdownEscaper escapes the characters that would end a cell or row of a Markdown table.
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+/*
+writeMarkdown writes records as a GitHub-flavored Markdown table, for pasting into pull requests and wiki pages. The
+first record is the header. Markdown tables need the same number of cells in every row, so shorter records, and the
+header of records with extra columns, are padded with empty cells.
+*/
+func writeMarkdown(w io.Writer, records [][]string) error {
+	columns := 0
+	for _, record := range records {
+		columns = max(columns, len(record))
+	}
+	var b strings.Builder
+	for i, record := range records {
+		b.Reset()
+		for j := 0; j < columns; j++ {
+			cell := ""
+			if j < len(record) {
+				cell = markdownEscaper.Replace(record[j])
+			}
+			b.WriteString("| " + cell + " ")
+		}
+		b.WriteString("|\n")
+		if i == 0 {
+			b.WriteString(strings.Repeat("| --- ", columns) + "|\n")
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return fmt.Errorf("failed to write markdown: %w", err)
+		}
+	}
+	return nil
+}
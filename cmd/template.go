@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// outputTemplate is the template given with the template flag, or nil if results are written in a built-in format.
+var outputTemplate *template.Template
+
+// templateFuncs are the functions available to output templates, besides the text/template builtins.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+}
+
+/*
+templateData is the data an output template is rendered with. It holds the fields of the JSON report, where Results
+maps each result set, like "A-B", to its elements with their Value, Count, Closest, Distance, and Columns, along with
+the Stats of both files.
+*/
+type templateData struct {
+	jsonReport
+	Stats setStats
+}
+
+/*
+setTemplate parses the output template given with the template flag. The value is read as a template file if a file by
+that name exists, and is used as the template itself if it contains an action, e.g. '{{range index .Results "A-B"}}
+{{.Value}}{{end}}'. It returns an error if the file can't be read or the template can't be parsed.
+*/
+func setTemplate(value string) error {
+	outputTemplate = nil
+	if value == "" {
+		return nil
+	}
+	text := value
+	if data, err := os.ReadFile(value); err == nil {
+		text = string(data)
+	} else if !strings.Contains(value, "{{") {
+		// a value without any action is a file name, not a template
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	t, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	outputTemplate = t
+	return nil
+}
+
+// writeTemplate renders the results through outputTemplate to w.
+func (r *results) writeTemplate(w io.Writer) error {
+	if err := outputTemplate.Execute(w, templateData{r.jsonReport(), r.stats()}); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+/*
+hashDir adds an element for every regular file below the directory at fs.path, its path relative to the directory
+followed by the SHA-256 of its content, like "etc/app.conf:9f86d0...". A file renamed without changes is reported under
+both names with the same hash, and a file changed in place under the same name with different hashes. Symlinks and
+other special files are skipped. Elements are added with addExact, since paths and hashes must be compared exactly.
+*/
+func (fs *fileSet) hashDir(ctx context.Context) error {
+	return filepath.WalkDir(fs.path, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(fs.path, path)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		fs.addExact(filepath.ToSlash(rel)+":"+sum, nil)
+		return nil
+	})
+}
+
+// hashFile returns the hex encoded SHA-256 of the content of the file at path.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+)
+
+// valueDelta is a key whose summed values differ between fileA and fileB.
+type valueDelta struct {
+	key     string
+	a       float64
+	b       float64
+	outlier bool // the delta is an outlier by the outliers rule
+}
+
+// outlierRule flags outlying deltas, either beyond k standard deviations from the mean or beyond k IQRs from the quartiles.
+type outlierRule struct {
+	method string // "stddev" or "iqr"
+	k      float64
+}
+
+// outlierRules holds the parsed outliers flag. Its method is empty if outliers are not flagged.
+var outlierRules outlierRule
+
+/*
+parseOutlierRule parses a rule in the form METHOD[:K], where METHOD is stddev or iqr. K defaults to 3 standard
+deviations or 1.5 IQRs. It returns an error if the method is unknown or K is not a positive number.
+*/
+func parseOutlierRule(s string) (outlierRule, error) {
+	method, k, hasK := strings.Cut(s, ":")
+	rule := outlierRule{method: method}
+	switch method {
+	case "stddev":
+		rule.k = 3
+	case "iqr":
+		rule.k = 1.5
+	default:
+		return rule, fmt.Errorf("invalid outlier rule: %s, expected stddev[:K] or iqr[:K]", s)
+	}
+	if hasK {
+		var err error
+		if rule.k, err = strconv.ParseFloat(k, 64); err != nil || rule.k <= 0 {
+			return rule, fmt.Errorf("invalid outlier threshold: %s", k)
+		}
+	}
+	return rule, nil
+}
+
+// String describes the rule for output headers.
+func (o outlierRule) String() string {
+	if o.method == "iqr" {
+		return translate("beyond %s IQRs of the quartiles", formatValue(o.k))
+	}
+	return translate("beyond %s standard deviations of the mean", formatValue(o.k))
+}
+
+// flagOutliers marks the deltas that are outliers by the rule.
+func (o outlierRule) flagOutliers(deltas []valueDelta) {
+	if len(deltas) == 0 {
+		return
+	}
+	values := make([]float64, len(deltas))
+	for i, d := range deltas {
+		values[i] = d.delta()
+	}
+	lo, hi := math.Inf(-1), math.Inf(1)
+	switch o.method {
+	case "stddev":
+		s := summarize(values)
+		var variance float64
+		for _, v := range values {
+			variance += (v - s.Mean) * (v - s.Mean)
+		}
+		sd := math.Sqrt(variance / float64(len(values)))
+		lo, hi = s.Mean-o.k*sd, s.Mean+o.k*sd
+	case "iqr":
+		sort.Float64s(values)
+		q1, q3 := percentile(values, 25), percentile(values, 75)
+		lo, hi = q1-o.k*(q3-q1), q3+o.k*(q3-q1)
+	}
+	for i := range deltas {
+		deltas[i].outlier = deltas[i].delta() < lo || deltas[i].delta() > hi
+	}
+}
+
+// outliers returns the deltas flagged as outliers.
+func (r *results) outliers() []valueDelta {
+	var outliers []valueDelta
+	for _, d := range r.deltas {
+		if d.outlier {
+			outliers = append(outliers, d)
+		}
+	}
+	return outliers
+}
+
+// jsonValueDelta is a differing key in the JSON output.
+type jsonValueDelta struct {
+	Key   string  `json:"key"`
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Delta float64 `json:"delta"`
+	// Percent is the delta relative to A, or null if A is 0.
+	Percent *float64 `json:"percent"`
+	Outlier bool     `json:"outlier,omitempty"`
+}
+
+// jsonValuesReport is the document written by the JSON output format when comparing values.
+type jsonValuesReport struct {
+	Operation   string           `json:"operation"`
+	FileA       string           `json:"fileA"`
+	FileB       string           `json:"fileB"`
+	Column      string           `json:"column"`
+	Differences []jsonValueDelta `json:"differences"`
+	Outliers    []jsonValueDelta `json:"outliers,omitempty"`
+}
+
+/*
+valueIndex resolves valueColumn, a 1-based column index of the rows of fs or a header name, into an index into their
+remaining columns, i.e. excluding the key. It returns an error if the column is the key column, is not a positive index,
+or is not found in the header of fs, which lists the key first.
+*/
+func (fs *fileSet) valueIndex() (int, error) {
+	n, err := strconv.Atoi(valueColumn)
+	if err != nil {
+		for i, name := range fs.header {
+			if !strings.EqualFold(name, valueColumn) {
+				continue
+			}
+			if i == 0 {
+				return 0, fmt.Errorf("value column %s is the key column", valueColumn)
+			}
+			return i - 1, nil
+		}
+		return 0, fmt.Errorf("value column not found in header of %s: %s", fs.path, valueColumn)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("invalid value column index: %d", n)
+	}
+	index, ok := fs.remainingIndex(n)
+	if !ok {
+		return 0, fmt.Errorf("value column %s is the key column", valueColumn)
+	}
+	return index, nil
+}
+
+// addValue adds the number in the value column of a row to the total of its key. Rows without a number are skipped.
+func (fs *fileSet) addValue(element string, columns []string) {
+	index, err := fs.valueIndex()
+	if err != nil || index >= len(columns) {
+		return
+	}
+	_, f, ok := diffit.CanonicalNumber(columns[index])
+	if !ok {
+		l.Trace().Str("element", element).Str("value", columns[index]).Msg("skipping non-numeric value")
+		return
+	}
+	fs.sums[element] += f
+}
+
+/*
+withinTolerance reports whether two numbers are equal within the tolerance and tolerance-percent flags. The percentage is
+relative to a. Without either flag, the numbers must be exactly equal.
+*/
+func withinTolerance(a, b float64) bool {
+	delta := math.Abs(b - a)
+	if delta == 0 {
+		return true
+	}
+	if tolerance > 0 && delta <= tolerance {
+		return true
+	}
+	return tolerancePct > 0 && delta <= math.Abs(a)*tolerancePct/100
+}
+
+/*
+compareValues sums the value column per key in each file and stores the keys whose totals differ in deltas, sorted by
+the magnitude of their delta so the biggest discrepancies come first, then by key. A key missing from one file has a
+total of 0 there. Totals within the tolerance flags are treated as equal. If the outliers flag is set, outlying deltas
+are flagged. It returns an error if the value column cannot be resolved in either file.
+*/
+func (r *results) compareValues() error {
+	r.operation = "values"
+	for _, fs := range []*fileSet{&r.fileSetA, &r.fileSetB} {
+		if _, err := fs.valueIndex(); err != nil {
+			return err
+		}
+	}
+	for _, key := range diffit.Union(r.fileSetA.set, r.fileSetB.set).Elements {
+		a, b := r.fileSetA.sums[key], r.fileSetB.sums[key]
+		if !withinTolerance(a, b) {
+			r.deltas = append(r.deltas, valueDelta{key: key, a: a, b: b})
+		}
+	}
+	sort.SliceStable(r.deltas, func(i, j int) bool {
+		return math.Abs(r.deltas[i].delta()) > math.Abs(r.deltas[j].delta())
+	})
+	if outlierRules.method != "" {
+		outlierRules.flagOutliers(r.deltas)
+	}
+	return nil
+}
+
+// delta returns the difference between the totals of fileB and fileA.
+func (d valueDelta) delta() float64 {
+	return d.b - d.a
+}
+
+// percent returns the delta as a percentage of the total of fileA. It returns false if that total is 0.
+func (d valueDelta) percent() (float64, bool) {
+	if d.a == 0 {
+		return 0, false
+	}
+	return d.delta() / math.Abs(d.a) * 100, true
+}
+
+// valueColumnName returns the display name of the value column.
+func (r *results) valueColumnName() string {
+	index, err := r.fileSetA.valueIndex()
+	if err != nil {
+		return valueColumn
+	}
+	return r.columnName(index)
+}
+
+// formatValue formats a total as the shortest decimal representation.
+func formatValue(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatDelta formats the difference between two totals with an explicit sign, rounding away floating-point noise.
+func formatDelta(d valueDelta) string {
+	delta := math.Round(d.delta()*1e9) / 1e9
+	if delta > 0 {
+		return "+" + formatValue(delta)
+	}
+	return formatValue(delta)
+}
+
+// formatPercent formats the delta as a signed percentage of the total of fileA, rounded to two decimals, or "n/a".
+func formatPercent(d valueDelta) string {
+	p, ok := d.percent()
+	if !ok {
+		return "n/a"
+	}
+	if p > 0 {
+		return "+" + strconv.FormatFloat(p, 'f', 2, 64) + "%"
+	}
+	return strconv.FormatFloat(p, 'f', 2, 64) + "%"
+}
+
+/*
+printValues prints the keys whose totals differ, with their delta and percentage, as plain text. If the outliers flag is
+set, the outlying keys are printed first in their own section. Headers are omitted if the pipe flag is set.
+*/
+func (r *results) printValues(w io.Writer) error {
+	if outlierRules.method != "" {
+		if !pipe {
+			fmt.Fprintln(w, translate("Outliers (deltas %s):", outlierRules))
+		}
+		if err := r.printDeltas(w, r.outliers()); err != nil {
+			return err
+		}
+		if !pipe {
+			fmt.Fprintln(w)
+		}
+	}
+	if !pipe {
+		fmt.Fprintln(w, translate("Totals of %s differing between %s and %s:", r.valueColumnName(), r.fileSetA.path, r.fileSetB.path))
+	}
+	return r.printDeltas(w, r.deltas)
+}
+
+// printDeltas prints each delta on its own line.
+func (r *results) printDeltas(w io.Writer, deltas []valueDelta) error {
+	for _, d := range deltas {
+		if _, err := fmt.Fprintf(w, "%s: %s -> %s (%s, %s)\n", r.display(d.key), formatValue(d.a), formatValue(d.b), formatDelta(d), formatPercent(d)); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+	}
+	return nil
+}
+
+/*
+writeValuesCSV writes the keys whose totals differ as CSV with key, a, b, delta, and percent columns. The percent column
+is empty if the total of fileA is 0. If the outliers flag is set, an outlier column holds true or false.
+*/
+func (r *results) writeValuesCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(r.valuesRecords()); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// valuesRecords returns the keys whose totals differ as records with key, a, b, delta, percent, and outlier columns.
+func (r *results) valuesRecords() [][]string {
+	header := []string{"key", "a", "b", "delta", "percent"}
+	if outlierRules.method != "" {
+		header = append(header, "outlier")
+	}
+	records := [][]string{header}
+	for _, d := range r.deltas {
+		var percent string
+		if p, ok := d.percent(); ok {
+			percent = strconv.FormatFloat(p, 'f', 2, 64)
+		}
+		record := []string{r.display(d.key), formatValue(d.a), formatValue(d.b), formatDelta(d), percent}
+		if outlierRules.method != "" {
+			record = append(record, strconv.FormatBool(d.outlier))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// writeValuesJSON writes the keys whose totals differ, and any outliers among them, as a single indented JSON document.
+func (r *results) writeValuesJSON(w io.Writer) error {
+	report := jsonValuesReport{
+		Operation:   r.operation,
+		FileA:       r.fileSetA.path,
+		FileB:       r.fileSetB.path,
+		Column:      r.valueColumnName(),
+		Differences: []jsonValueDelta{},
+	}
+	for _, d := range r.deltas {
+		delta := jsonValueDelta{Key: r.display(d.key), A: d.a, B: d.b, Delta: d.delta(), Outlier: d.outlier}
+		if p, ok := d.percent(); ok {
+			delta.Percent = &p
+		}
+		report.Differences = append(report.Differences, delta)
+		if d.outlier {
+			report.Outliers = append(report.Outliers, delta)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// writeValuesQuickfix writes the keys whose totals differ as "file:line: message" entries for editors, marking outliers.
+func (r *results) writeValuesQuickfix(w io.Writer) error {
+	for _, d := range r.deltas {
+		path, line := location(d.key, &r.fileSetB, &r.fileSetA)
+		var prefix string
+		if d.outlier {
+			prefix = "outlier: "
+		}
+		if _, err := fmt.Fprintf(w, "%s:%d: %s%s: %s -> %s (%s, %s)\n", path, line, prefix, r.display(d.key), formatValue(d.a), formatValue(d.b), formatDelta(d), formatPercent(d)); err != nil {
+			return fmt.Errorf("failed to write quickfix: %w", err)
+		}
+	}
+	return nil
+}
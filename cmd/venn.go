@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+writeVenn writes a text Venn diagram of the two file sets, with the number of elements only in fileA, in both, and only
+in fileB in its three regions, and the file names below the circles:
+
+	  ____________ _____ ____________
+	 /            /     \            \
+	|      12     |  3  |     5      |
+	 \____________\_____/____________/
+	     a.txt              b.txt
+
+The regions widen to fit larger counts and longer names.
+*/
+func (r *results) writeVenn(w io.Writer) error {
+	s := r.stats()
+	onlyA, both, onlyB := strconv.Itoa(s.OnlyA), strconv.Itoa(s.Both), strconv.Itoa(s.OnlyB)
+	side := max(12, len(onlyA)+4, len(onlyB)+4, displayWidth(r.fileSetA.path)+2, displayWidth(r.fileSetB.path)+2)
+	middle := max(5, len(both)+2)
+	lines := []string{
+		"  " + strings.Repeat("_", side) + " " + strings.Repeat("_", middle) + " " + strings.Repeat("_", side),
+		" /" + strings.Repeat(" ", side) + "/" + strings.Repeat(" ", middle) + "\\" + strings.Repeat(" ", side) + "\\",
+		"|" + center(onlyA, side+1) + "|" + center(both, middle) + "|" + center(onlyB, side) + "|",
+		" \\" + strings.Repeat("_", side) + "\\" + strings.Repeat("_", middle) + "/" + strings.Repeat("_", side) + "/",
+		strings.TrimRight("  "+center(r.fileSetA.path, side)+strings.Repeat(" ", middle+2)+center(r.fileSetB.path, side), " "),
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write venn diagram: %w", err)
+		}
+	}
+	return nil
+}
+
+// center pads s with spaces on both sides to the display width of width, with any odd space on the right.
+func center(s string, width int) string {
+	pad := max(0, width-displayWidth(s))
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long --watch waits for changes to settle before re-running, since editors often write a file in
+// several steps.
+const watchDebounce = 200 * time.Millisecond
+
+// clearScreen moves the cursor home and clears a terminal.
+const clearScreen = "\033[H\033[2J"
+
+/*
+watchInputs calls run, then calls it again whenever one of the local files or directories in paths changes, until ctx is
+canceled. The parent directory of each file is watched rather than the file itself, so files replaced by a rename, as
+many editors save them, are still followed. Errors from run are logged and watching continues.
+*/
+func watchInputs(ctx context.Context, paths []string, run func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch inputs: %w", err)
+	}
+	defer watcher.Close()
+
+	watched := map[string]bool{}
+	for _, path := range paths {
+		if path == stdinPath || strings.Contains(path, "://") {
+			continue
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		dir := filepath.Dir(abs)
+		if info, err := os.Stat(abs); err == nil && info.IsDir() {
+			dir = abs
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		watched[abs] = true
+	}
+	if len(watched) == 0 {
+		return fmt.Errorf("--watch needs at least one local file to watch")
+	}
+
+	rerun := func() {
+		if outputPath == "" || outputPath == "-" {
+			if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				fmt.Fprint(os.Stdout, clearScreen)
+			}
+		}
+		if err := run(); err != nil {
+			l.Error().Err(err).Send()
+		}
+	}
+	rerun()
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// only content changes matter, and files in a watched directory are only inputs if they were named
+			if event.Op == fsnotify.Chmod || !isWatched(watched, event.Name) {
+				continue
+			}
+			l.Debug().Str("file", event.Name).Str("op", event.Op.String()).Msg("input changed")
+			timer.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			l.Error().Err(err).Msg("failed to watch inputs")
+		case <-timer.C:
+			rerun()
+		}
+	}
+}
+
+// isWatched reports whether the file at name is an input: a watched file or glob, or a file in a watched directory.
+func isWatched(watched map[string]bool, name string) bool {
+	if watched[name] || watched[filepath.Dir(name)] {
+		return true
+	}
+	for pattern := range watched {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
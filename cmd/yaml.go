@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+parseYAML decodes every YAML document in r and adds their entries to the set with addValues: the items of a list, or
+the keys of a map, like the hosts of an Ansible inventory group. With keyField, each map document, like a Kubernetes
+manifest, is an object keyed on that field. If jsonPath is set, the values it selects in each document are added
+instead.
+*/
+func (fs *fileSet) parseYAML(r io.Reader) error {
+	dec := yaml.NewDecoder(r)
+	var values []any
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to decode yaml: %w", err)
+		}
+		doc := yamlValue(&node)
+		switch list, ok := doc.([]any); {
+		case jsonPath != "":
+			selected, err := evalJSONPath(doc, jsonPath)
+			if err != nil {
+				return err
+			}
+			values = append(values, selected...)
+		case ok:
+			values = append(values, list...)
+		default:
+			values = append(values, doc)
+		}
+	}
+	return fs.addValues(values)
+}
+
+/*
+yamlValue converts a YAML node to the types of a JSON document decoded with UseNumber, so json paths, key fields, and
+jsonScalar treat both alike. Scalars keep their text as written, so a date or a hex number isn't reformatted, and
+aliases and merge keys are resolved.
+*/
+func yamlValue(node *yaml.Node) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return yamlValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, len(node.Content))
+		for i, item := range node.Content {
+			list[i] = yamlValue(item)
+		}
+		return list
+	case yaml.MappingNode:
+		m := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				yamlMerge(m, yamlValue(value))
+				continue
+			}
+			m[key.Value] = yamlValue(value)
+		}
+		return m
+	}
+	switch node.Tag {
+	case "!!null":
+		return nil
+	case "!!bool":
+		var b bool
+		if node.Decode(&b) == nil {
+			return b
+		}
+	case "!!int", "!!float":
+		return json.Number(node.Value)
+	}
+	return node.Value
+}
+
+// yamlMerge adds the keys merged with "<<" from v, a map or a list of maps, to m. Keys already in m take precedence.
+func yamlMerge(m map[string]any, v any) {
+	sources, ok := v.([]any)
+	if !ok {
+		sources = []any{v}
+	}
+	for _, source := range sources {
+		merged, ok := source.(map[string]any)
+		if !ok {
+			continue
+		}
+		for key, value := range merged {
+			if _, ok := m[key]; !ok {
+				m[key] = value
+			}
+		}
+	}
+}
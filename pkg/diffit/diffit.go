@@ -0,0 +1,408 @@
+/*
+Package diffit compares lists as sets, the engine behind the goDiffIt CLI. Elements are normalized as they are added to
+a Set, according to its Options, so lists from different sources that only differ in case, accents, domain suffixes, or
+number formatting compare equal. Two sets are then compared with Difference, Intersection, or Union.
+
+	a, b := diffit.NewSet(opts), diffit.NewSet(opts)
+	if err := a.Read(fileA); err != nil {
+		return err
+	}
+	if err := b.Read(fileB); err != nil {
+		return err
+	}
+	result := diffit.Difference(a, b)
+*/
+package diffit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Options control how lines are split into elements and how elements are normalized. The zero value compares whole
+// lines case insensitively.
+type Options struct {
+	// CaseSensitive disables case folding elements.
+	CaseSensitive bool
+	// Locale is the BCP 47 language tag whose casing rules lowercase elements, e.g. "tr". Empty applies Unicode case
+	// folding, which is language independent.
+	Locale string
+	// FoldAccents removes accents and other combining marks from elements, so "Åland" matches "Aland".
+	FoldAccents bool
+	// UnicodeForm is the Unicode normalization form elements are converted to, "NFC" or "NFKC". Empty keeps them as is.
+	UnicodeForm string
+	// Trim removes the leading and trailing content of elements matched by each pattern, in order.
+	Trim []*regexp.Regexp
+	// Resolve, if set, replaces each element before it is normalized, e.g. an IP address by its host name.
+	Resolve func(element string) string
+	// IgnoreFQDN compares only the host name of fully qualified domain names, or the part selected by FQDNMode.
+	IgnoreFQDN bool
+	// FQDNMode is the FQDN mode used by IgnoreFQDN, see StripDomain. Empty is FQDNHost.
+	FQDNMode string
+	// Numeric compares elements as numbers, skipping anything that isn't one.
+	Numeric bool
+	// NumericRange, if set, reports whether a number is kept when Numeric is set.
+	NumericRange func(n float64) bool
+	// StripPunct removes the characters it matches from elements, e.g. regexp.MustCompile("[[:punct:]]").
+	StripPunct *regexp.Regexp
+	// StripLeadingZeros removes leading zeros from every number within an element.
+	StripLeadingZeros bool
+	// CommentPrefix skips lines read that start with it, after any indentation. Empty keeps every line.
+	CommentPrefix string
+	// Extract replaces each line read by the part matched by the first pattern matching it, see Set.RewriteLine.
+	Extract []*regexp.Regexp
+	// StripTimestamps removes a leading timestamp from each line read.
+	StripTimestamps bool
+	// Templates reduces each line read to a log template, see LogTemplate.
+	Templates bool
+	// Replace rewrites each line read with each replacement, in order.
+	Replace []Replacement
+	// Delimiter splits each line read into fields, see SplitDelimited. Empty keeps lines whole.
+	Delimiter string
+	// Field is the 1-based field of each line used as the element, the remaining fields are its columns. 0 is the first.
+	Field int
+	// Header treats the first line read as a header row rather than an element.
+	Header bool
+	// MinLength and MaxLength skip elements shorter or longer than this many characters. A MaxLength of 0 is no limit.
+	MinLength, MaxLength int
+}
+
+// Replacement is a regular expression substitution applied to each line read. With may refer to submatches as $1.
+type Replacement struct {
+	Pattern *regexp.Regexp
+	With    string
+}
+
+// trimPattern removes the content matched by a pattern of Options.Trim from either end of an element.
+type trimPattern struct {
+	leading  *regexp.Regexp
+	trailing *regexp.Regexp
+}
+
+/*
+Set is a set of normalized elements, each with the columns of the first row it was read from. The casers and
+transformers it normalizes with keep state, so a set must not be used by several goroutines at once.
+*/
+type Set struct {
+	opts     Options
+	caser    cases.Caser
+	accents  transform.Transformer
+	form     *norm.Form
+	trim     []trimPattern
+	elements map[string][]string
+	// Header holds the fields of the header row, if Options.Header is set.
+	Header []string
+}
+
+/*
+NewSet returns an empty set normalizing elements with opts. An invalid Options.Locale is treated as empty, and an
+invalid Options.UnicodeForm keeps elements as they are.
+*/
+func NewSet(opts Options) *Set {
+	s := &Set{opts: opts, caser: cases.Fold(), elements: map[string][]string{}}
+	if opts.Locale != "" {
+		if tag, err := language.Parse(opts.Locale); err == nil {
+			s.caser = cases.Lower(tag)
+		}
+	}
+	if opts.FoldAccents {
+		s.accents = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	}
+	switch strings.ToUpper(opts.UnicodeForm) {
+	case "NFC":
+		f := norm.NFC
+		s.form = &f
+	case "NFKC":
+		f := norm.NFKC
+		s.form = &f
+	}
+	for _, p := range opts.Trim {
+		s.trim = append(s.trim, trimPattern{
+			leading:  regexp.MustCompile(`^(?:` + p.String() + `)`),
+			trailing: regexp.MustCompile(`(?:` + p.String() + `)$`),
+		})
+	}
+	return s
+}
+
+// Normalized is an element at the stages of its normalization that are of interest to callers.
+type Normalized struct {
+	// Name is the element once trimmed, resolved, and converted to Options.UnicodeForm, before its domain is removed.
+	Name string
+	// Spelling is the element before case folding and the removal of accents, punctuation, and leading zeros, which is
+	// how it is shown to users.
+	Spelling string
+	// Key is the fully normalized element, which the set is keyed on.
+	Key string
+}
+
+/*
+NormalizeStages normalizes element according to the options of the set, in this order: trim patterns are removed, the
+element is resolved and converted to the Unicode normalization form, its domain suffix is removed, and numbers are
+canonicalized, which gives its spelling. It is then case folded, and accents, punctuation, and leading zeros are
+removed, which gives its key. It returns false if the element should be skipped, because it isn't a number in
+Options.NumericRange when Options.Numeric is set, or its key is outside of the length limits.
+*/
+func (s *Set) NormalizeStages(element string) (Normalized, bool) {
+	for _, p := range s.trim {
+		element = p.leading.ReplaceAllString(element, "")
+		element = p.trailing.ReplaceAllString(element, "")
+	}
+	if s.opts.Resolve != nil {
+		element = s.opts.Resolve(element)
+	}
+	if s.form != nil {
+		element = s.form.String(element)
+	}
+	n := Normalized{Name: element}
+	if s.opts.IgnoreFQDN {
+		element = StripDomain(element, s.opts.FQDNMode)
+	}
+	if s.opts.Numeric {
+		canonical, f, ok := CanonicalNumber(element)
+		if !ok || (s.opts.NumericRange != nil && !s.opts.NumericRange(f)) {
+			return n, false
+		}
+		element = canonical
+	}
+	n.Spelling = element
+	if !s.opts.CaseSensitive {
+		element = s.FoldCase(element)
+	}
+	if s.accents != nil {
+		if folded, _, err := transform.String(s.accents, element); err == nil {
+			element = folded
+		}
+	}
+	if s.opts.StripPunct != nil {
+		element = s.opts.StripPunct.ReplaceAllString(element, "")
+	}
+	if s.opts.StripLeadingZeros {
+		element = StripLeadingZeros(element)
+	}
+	n.Key = element
+	if l := utf8.RuneCountInString(element); l < s.opts.MinLength || (s.opts.MaxLength > 0 && l > s.opts.MaxLength) {
+		return n, false
+	}
+	return n, true
+}
+
+// Normalize returns the key of element, as described by NormalizeStages, or false if the element should be skipped.
+func (s *Set) Normalize(element string) (string, bool) {
+	n, ok := s.NormalizeStages(element)
+	return n.Key, ok
+}
+
+/*
+FoldCase applies Unicode case folding to element, so spellings like "straße" and "STRASSE" match, or lowercases it
+using the casing rules of Options.Locale, if set.
+*/
+func (s *Set) FoldCase(element string) string {
+	return s.caser.String(element)
+}
+
+// Add normalizes element and adds it to the set with columns, unless it was already added. It returns false if the
+// element was skipped by Normalize.
+func (s *Set) Add(element string, columns []string) bool {
+	key, ok := s.Normalize(element)
+	if !ok {
+		return false
+	}
+	s.Insert(key, columns)
+	return true
+}
+
+// Insert adds the already normalized element key to the set with columns, unless it was already added.
+func (s *Set) Insert(key string, columns []string) {
+	if _, ok := s.elements[key]; !ok {
+		s.elements[key] = columns
+	}
+}
+
+// IsComment reports whether line starts with Options.CommentPrefix, after any indentation.
+func (s *Set) IsComment(line string) bool {
+	return s.opts.CommentPrefix != "" && strings.HasPrefix(strings.TrimSpace(line), s.opts.CommentPrefix)
+}
+
+/*
+RewriteLine rewrites a line read before it is split into fields. If Options.Extract is set, the line is replaced by the
+part matched by the first pattern matching it: the first capture group, if the pattern has one, or else the whole
+match. A leading timestamp is then removed, the line is reduced to its log template, and the replacements are applied,
+if those options are set. It returns false if the line should be skipped, because it matches no extract pattern.
+*/
+func (s *Set) RewriteLine(line string) (string, bool) {
+	if len(s.opts.Extract) > 0 {
+		var ok bool
+		if line, ok = extract(line, s.opts.Extract); !ok {
+			return "", false
+		}
+	}
+	if s.opts.StripTimestamps {
+		line = StripTimestamp(line)
+	}
+	if s.opts.Templates {
+		line = LogTemplate(line)
+	}
+	for _, r := range s.opts.Replace {
+		line = r.Pattern.ReplaceAllString(line, r.With)
+	}
+	return line, true
+}
+
+// extract returns the part of line matched by the first of patterns matching it, as described by RewriteLine.
+func extract(line string, patterns []*regexp.Regexp) (string, bool) {
+	for _, p := range patterns {
+		m := p.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if len(m) > 1 {
+			return m[1], true
+		}
+		return m[0], true
+	}
+	return "", false
+}
+
+/*
+Read adds each line read from r to the set. Comments are skipped and lines are rewritten with RewriteLine, then blank
+lines are skipped. Lines are split with SplitFields, and Options.Field is used as the element, with the remaining fields
+kept as its columns. Lines with fewer fields are skipped.
+*/
+func (s *Set) Read(r io.Reader) error {
+	field := max(s.opts.Field, 1)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if s.IsComment(line) {
+			continue
+		}
+		line, ok := s.RewriteLine(line)
+		if !ok || strings.TrimSpace(line) == "" {
+			continue
+		}
+		element, columns, ok := SplitFields(line, s.opts.Delimiter, field)
+		if !ok {
+			continue
+		}
+		if s.opts.Header && s.Header == nil {
+			s.Header = append([]string{element}, columns...)
+			continue
+		}
+		s.Add(element, columns)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of elements in the set.
+func (s *Set) Len() int {
+	return len(s.elements)
+}
+
+// Contains reports whether the normalized element key is in the set.
+func (s *Set) Contains(key string) bool {
+	_, ok := s.elements[key]
+	return ok
+}
+
+// Columns returns the columns stored for the normalized element key, or nil if there are none.
+func (s *Set) Columns(key string) []string {
+	return s.elements[key]
+}
+
+// Keys returns the normalized elements of the set, in no particular order.
+func (s *Set) Keys() []string {
+	keys := make([]string, 0, len(s.elements))
+	for key := range s.elements {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Elements returns the normalized elements of the set, sorted.
+func (s *Set) Elements() []string {
+	keys := s.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+// Operation is a set operation.
+type Operation string
+
+// The set operations supported by Compare.
+const (
+	OpDifference   Operation = "difference"
+	OpIntersection Operation = "intersection"
+	OpUnion        Operation = "union"
+)
+
+// Result is the result of comparing two sets. Elements are sorted.
+type Result struct {
+	Operation Operation
+	// Elements holds A - B for a difference, or the intersection or union of A and B.
+	Elements []string
+	// Reverse holds B - A for a difference, and is nil otherwise.
+	Reverse []string
+}
+
+// Compare performs op on a and b. It returns an error if op is not a known operation.
+func Compare(a, b *Set, op Operation) (Result, error) {
+	switch op {
+	case OpDifference:
+		return Difference(a, b), nil
+	case OpIntersection:
+		return Intersection(a, b), nil
+	case OpUnion:
+		return Union(a, b), nil
+	}
+	return Result{}, fmt.Errorf("invalid operation: %s", op)
+}
+
+// Difference returns the elements only in a, and in Result.Reverse, the elements only in b.
+func Difference(a, b *Set) Result {
+	return Result{Operation: OpDifference, Elements: a.without(b), Reverse: b.without(a)}
+}
+
+// Intersection returns the elements in both a and b.
+func Intersection(a, b *Set) Result {
+	var elements []string
+	for _, key := range a.Elements() {
+		if b.Contains(key) {
+			elements = append(elements, key)
+		}
+	}
+	return Result{Operation: OpIntersection, Elements: elements}
+}
+
+// Union returns the elements in a, b, or both.
+func Union(a, b *Set) Result {
+	elements := append(a.Elements(), b.without(a)...)
+	sort.Strings(elements)
+	return Result{Operation: OpUnion, Elements: elements}
+}
+
+// without returns the sorted elements of s that are not in other.
+func (s *Set) without(other *Set) []string {
+	var elements []string
+	for _, key := range s.Elements() {
+		if !other.Contains(key) {
+			elements = append(elements, key)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,187 @@
+package diffit
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		element string
+		want    Normalized
+		wantOK  bool
+	}{
+		{
+			name:    "zero value folds case",
+			element: "Straße",
+			want:    Normalized{Name: "Straße", Spelling: "Straße", Key: "strasse"},
+			wantOK:  true,
+		},
+		{
+			name:    "case sensitive",
+			opts:    Options{CaseSensitive: true},
+			element: "Web01",
+			want:    Normalized{Name: "Web01", Spelling: "Web01", Key: "Web01"},
+			wantOK:  true,
+		},
+		{
+			name:    "turkish locale",
+			opts:    Options{Locale: "tr"},
+			element: "ISTANBUL",
+			want:    Normalized{Name: "ISTANBUL", Spelling: "ISTANBUL", Key: "ıstanbul"},
+			wantOK:  true,
+		},
+		{
+			// the domain is removed after trimming, and the name is recorded before it is
+			name:    "trim before fqdn",
+			opts:    Options{Trim: []*regexp.Regexp{regexp.MustCompile(`\d+:\s*`)}, IgnoreFQDN: true},
+			element: "0001: Web01.Example.com",
+			want:    Normalized{Name: "Web01.Example.com", Spelling: "Web01", Key: "web01"},
+			wantOK:  true,
+		},
+		{
+			name:    "resolve before fqdn",
+			opts:    Options{Resolve: func(string) string { return "web01.example.com" }, IgnoreFQDN: true},
+			element: "10.0.0.1",
+			want:    Normalized{Name: "web01.example.com", Spelling: "web01", Key: "web01"},
+			wantOK:  true,
+		},
+		{
+			name:    "unicode form before accents",
+			opts:    Options{UnicodeForm: "NFKC", FoldAccents: true},
+			element: "Ｅｌａｎｄ Åland",
+			want:    Normalized{Name: "Eland Åland", Spelling: "Eland Åland", Key: "eland aland"},
+			wantOK:  true,
+		},
+		{
+			name:    "numbers canonicalized before leading zeros",
+			opts:    Options{Numeric: true, StripLeadingZeros: true},
+			element: "007.50",
+			want:    Normalized{Name: "007.50", Spelling: "7.5", Key: "7.5"},
+			wantOK:  true,
+		},
+		{
+			name:    "non-number skipped",
+			opts:    Options{Numeric: true},
+			element: "seven",
+			wantOK:  false,
+		},
+		{
+			name:    "number outside range skipped",
+			opts:    Options{Numeric: true, NumericRange: func(n float64) bool { return n < 10 }},
+			element: "42",
+			wantOK:  false,
+		},
+		{
+			// punctuation is removed after case folding, from the key only
+			name:    "punctuation and zeros",
+			opts:    Options{StripPunct: regexp.MustCompile(`[-_.]`), StripLeadingZeros: true},
+			element: "INV-007",
+			want:    Normalized{Name: "INV-007", Spelling: "INV-007", Key: "inv7"},
+			wantOK:  true,
+		},
+		{
+			// the length limits apply to the key, after punctuation is removed
+			name:    "too short once normalized",
+			opts:    Options{StripPunct: regexp.MustCompile(`[[:punct:]]`), MinLength: 3},
+			element: "a-b",
+			wantOK:  false,
+		},
+		{
+			name:    "too long",
+			opts:    Options{MaxLength: 3},
+			element: "abcd",
+			wantOK:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NewSet(tt.opts).NormalizeStages(tt.element)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("NormalizeStages(%q) = %+v, %v, want %+v, %v", tt.element, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRewriteLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		line   string
+		want   string
+		wantOK bool
+	}{
+		{"unchanged", Options{}, "web01", "web01", true},
+		{"extract group", Options{Extract: []*regexp.Regexp{regexp.MustCompile(`host=(\S+)`)}}, "id=1 host=web01", "web01", true},
+		{"extract match", Options{Extract: []*regexp.Regexp{regexp.MustCompile(`web\d+`)}}, "id=1 web01 up", "web01", true},
+		{"extract miss", Options{Extract: []*regexp.Regexp{regexp.MustCompile(`host=(\S+)`)}}, "id=1", "", false},
+		{
+			name:   "timestamp, template, then replace",
+			opts:   Options{StripTimestamps: true, Templates: true, Replace: []Replacement{{regexp.MustCompile(`<num>`), "N"}}},
+			line:   "2006-01-02T15:04:05Z took 42 ms",
+			want:   "took N ms",
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NewSet(tt.opts).RewriteLine(tt.line)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RewriteLine(%q) = %q, %v, want %q, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	s := NewSet(Options{Delimiter: ",", Field: 2, Header: true, CommentPrefix: "#"})
+	input := "id,host,role\n# decommissioned\n1,Web01,app\n\n2,web01,db\n3\n4,\"db,01\",db\n"
+	if err := s.Read(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"host", "id", "role"}; !reflect.DeepEqual(s.Header, want) {
+		t.Errorf("Header = %q, want %q", s.Header, want)
+	}
+	if want := []string{"db,01", "web01"}; !reflect.DeepEqual(s.Elements(), want) {
+		t.Errorf("Elements() = %q, want %q", s.Elements(), want)
+	}
+	// the columns of the first row read are kept
+	if want := []string{"1", "app"}; !reflect.DeepEqual(s.Columns("web01"), want) {
+		t.Errorf("Columns(web01) = %q, want %q", s.Columns("web01"), want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a, b := NewSet(Options{}), NewSet(Options{})
+	for _, e := range []string{"web03", "Web01", "web02"} {
+		a.Add(e, nil)
+	}
+	for _, e := range []string{"web02", "WEB01", "web04"} {
+		b.Add(e, nil)
+	}
+	tests := []struct {
+		op   Operation
+		want Result
+	}{
+		{OpDifference, Result{Operation: OpDifference, Elements: []string{"web03"}, Reverse: []string{"web04"}}},
+		{OpIntersection, Result{Operation: OpIntersection, Elements: []string{"web01", "web02"}}},
+		{OpUnion, Result{Operation: OpUnion, Elements: []string{"web01", "web02", "web03", "web04"}}},
+	}
+	for _, tt := range tests {
+		got, err := Compare(a, b, tt.op)
+		if err != nil {
+			t.Fatalf("Compare(%s) returned %v", tt.op, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Compare(%s) = %+v, want %+v", tt.op, got, tt.want)
+		}
+	}
+	if _, err := Compare(a, b, "xor"); err == nil {
+		t.Error("Compare(xor) returned no error")
+	}
+}
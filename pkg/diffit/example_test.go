@@ -0,0 +1,25 @@
+package diffit_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JakeTRogers/goDiffIt/pkg/diffit"
+)
+
+func ExampleDifference() {
+	opts := diffit.Options{IgnoreFQDN: true}
+	a, b := diffit.NewSet(opts), diffit.NewSet(opts)
+	if err := a.Read(strings.NewReader("web01.example.com\nWEB02.example.com\ndb01.example.com\n")); err != nil {
+		panic(err)
+	}
+	if err := b.Read(strings.NewReader("web01\nweb02\nweb03\n")); err != nil {
+		panic(err)
+	}
+	result := diffit.Difference(a, b)
+	fmt.Println("only in a:", result.Elements)
+	fmt.Println("only in b:", result.Reverse)
+	// Output:
+	// only in a: [db01]
+	// only in b: [web03]
+}
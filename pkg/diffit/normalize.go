@@ -0,0 +1,163 @@
+package diffit
+
+import (
+	"encoding/csv"
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+/*
+timestampPattern matches common leading timestamp formats, optionally wrapped in square brackets, along with any
+following whitespace:
+  - syslog, e.g. "Jan  2 15:04:05"
+  - ISO 8601, e.g. "2006-01-02T15:04:05.000Z" or "2006-01-02 15:04:05,000"
+  - epoch seconds, milliseconds, microseconds, or nanoseconds, e.g. "1136214245" or "1136214245.123"
+*/
+var timestampPattern = regexp.MustCompile(`^\[?(?:` +
+	`[A-Z][a-z]{2} [ 0-9]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?` +
+	`|\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?` +
+	`|\d{10}(?:\d{3}|\d{6}|\d{9})?(?:\.\d+)?` +
+	`)\]?(?:\s+|$)`)
+
+// StripTimestamp removes a leading timestamp from line, if it has one.
+func StripTimestamp(line string) string {
+	return timestampPattern.ReplaceAllString(line, "")
+}
+
+// templateMasks are applied in order to reduce a log line to its template. More specific patterns must come first.
+var templateMasks = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]*[0-9][0-9a-f]*[a-f][0-9a-f]*\b|\b[0-9a-f]*[a-f][0-9a-f]*[0-9][0-9a-f]*\b`), "<hex>"},
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?`), "<num>"},
+}
+
+/*
+LogTemplate reduces line to a template by masking the variable parts of log messages: UUIDs, IP addresses, hex ids, and
+numbers. Lines that only differ by those values share a template.
+*/
+func LogTemplate(line string) string {
+	for _, m := range templateMasks {
+		line = m.pattern.ReplaceAllString(line, m.replacement)
+	}
+	return line
+}
+
+// digitsPattern matches runs of digits within an element.
+var digitsPattern = regexp.MustCompile(`\d+`)
+
+// StripLeadingZeros removes leading zeros from each run of digits in element, keeping at least one digit, so "INV-007"
+// becomes "INV-7" and "0000" becomes "0".
+func StripLeadingZeros(element string) string {
+	return digitsPattern.ReplaceAllStringFunc(element, func(digits string) string {
+		if trimmed := strings.TrimLeft(digits, "0"); trimmed != "" {
+			return trimmed
+		}
+		return "0"
+	})
+}
+
+/*
+CanonicalNumber parses element as a number and returns it in canonical form, so "007", "7", and "7.0" are all "7".
+Integers are kept exact, anything else is formatted as the shortest float representation. It returns false if element
+is not a number.
+*/
+func CanonicalNumber(element string) (string, float64, bool) {
+	element = strings.TrimSpace(element)
+	if i, err := strconv.ParseInt(element, 10, 64); err == nil {
+		return strconv.FormatInt(i, 10), float64(i), true
+	}
+	f, err := strconv.ParseFloat(element, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", 0, false
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), f, true
+}
+
+// StripFQDN returns the host name of a fully qualified domain name, i.e. everything before the first dot.
+func StripFQDN(element string) string {
+	host, _, _ := strings.Cut(element, ".")
+	return host
+}
+
+// FQDN modes select the part of a fully qualified domain name kept by StripDomain.
+const (
+	// FQDNHost keeps the host name, everything before the first dot, like StripFQDN.
+	FQDNHost = "host"
+	// FQDNSubdomain keeps everything before the registrable domain, so host.a.example.com becomes host.a.
+	FQDNSubdomain = "subdomain"
+	// FQDNDomain keeps only the registrable domain, the public suffix and one more label, like example.co.uk.
+	FQDNDomain = "domain"
+)
+
+/*
+StripDomain returns the part of the fully qualified domain name element selected by mode, one of the FQDN modes. The
+subdomain and domain modes find the registrable domain with the public suffix list, so multi-label suffixes like co.uk
+are handled, and hosts in different subdomains of the same domain stay distinct. Elements that are a registrable domain
+or public suffix themselves, like a bare example.com, and IP addresses, are returned as they are in those modes. A
+trailing dot is ignored.
+*/
+func StripDomain(element, mode string) string {
+	if mode == "" || mode == FQDNHost {
+		return StripFQDN(element)
+	}
+	name := strings.TrimSuffix(element, ".")
+	if net.ParseIP(name) != nil {
+		return element
+	}
+	// the public suffix list is lowercase, so the domain is looked up lowercased and cut from the original spelling
+	domain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(name))
+	if err != nil || len(domain) >= len(name) {
+		return name
+	}
+	if mode == FQDNDomain {
+		return name[len(name)-len(domain):]
+	}
+	return name[:len(name)-len(domain)-1]
+}
+
+/*
+SplitDelimited splits line into fields by delimiter. Fields quoted as in RFC 4180 CSV, like "Smith, John", may contain
+the delimiter and doubled quotes, and are unquoted. Quotes within an unquoted field are kept as they are, so lines that
+merely contain a quote split the same as with strings.Split. Delimiters longer than one character don't support quoting.
+*/
+func SplitDelimited(line, delimiter string) []string {
+	comma, size := utf8.DecodeRuneInString(delimiter)
+	if !strings.Contains(line, `"`) || size != len(delimiter) {
+		return strings.Split(line, delimiter)
+	}
+	r := csv.NewReader(strings.NewReader(line))
+	r.Comma = comma
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	fields, err := r.Read()
+	if err != nil {
+		return strings.Split(line, delimiter)
+	}
+	return fields
+}
+
+/*
+SplitFields splits line with SplitDelimited into the 1-based field n and the remaining fields, in their original order.
+An empty delimiter keeps the line whole. It returns false if line has fewer than n fields.
+*/
+func SplitFields(line, delimiter string, n int) (string, []string, bool) {
+	fields := []string{line}
+	if delimiter != "" {
+		fields = SplitDelimited(line, delimiter)
+	}
+	if n > len(fields) {
+		return "", nil, false
+	}
+	return fields[n-1], append(fields[:n-1:n-1], fields[n:]...), true
+}
@@ -0,0 +1,159 @@
+package diffit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitDelimited(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		delimiter string
+		want      []string
+	}{
+		{"plain", "a,b,c", ",", []string{"a", "b", "c"}},
+		{"empty fields", "a,,c,", ",", []string{"a", "", "c", ""}},
+		{"quoted delimiter", `"Smith, John",42`, ",", []string{"Smith, John", "42"}},
+		{"doubled quotes", `"say ""hi""",x`, ",", []string{`say "hi"`, "x"}},
+		{"quote within unquoted field", `5" disk,x`, ",", []string{`5" disk`, "x"}},
+		{"tab", "a\t\"b\tc\"", "\t", []string{"a", "b\tc"}},
+		{"multi-character delimiter ignores quotes", `"a::b"::c`, "::", []string{`"a`, `b"`, "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitDelimited(tt.line, tt.delimiter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitDelimited(%q, %q) = %q, want %q", tt.line, tt.delimiter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		delimiter   string
+		n           int
+		wantElement string
+		wantColumns []string
+		wantOK      bool
+	}{
+		{"first field", "a,b,c", ",", 1, "a", []string{"b", "c"}, true},
+		{"middle field keeps column order", "a,b,c", ",", 2, "b", []string{"a", "c"}, true},
+		{"last field", "a,b,c", ",", 3, "c", []string{"a", "b"}, true},
+		{"too few fields", "a,b", ",", 3, "", nil, false},
+		{"empty delimiter keeps line whole", "a,b", "", 1, "a,b", []string{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			element, columns, ok := SplitFields(tt.line, tt.delimiter, tt.n)
+			if element != tt.wantElement || ok != tt.wantOK || (ok && !reflect.DeepEqual(columns, tt.wantColumns)) {
+				t.Errorf("SplitFields(%q, %q, %d) = %q, %q, %v, want %q, %q, %v", tt.line, tt.delimiter, tt.n,
+					element, columns, ok, tt.wantElement, tt.wantColumns, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStripDomain(t *testing.T) {
+	tests := []struct {
+		element string
+		mode    string
+		want    string
+	}{
+		{"web01.corp.example.com", "", "web01"},
+		{"web01.corp.example.com", FQDNHost, "web01"},
+		{"web01.corp.example.com", FQDNSubdomain, "web01.corp"},
+		{"web01.corp.example.com", FQDNDomain, "example.com"},
+		{"web01.example.co.uk", FQDNSubdomain, "web01"},
+		{"web01.example.co.uk", FQDNDomain, "example.co.uk"},
+		{"Web01.Example.COM", FQDNDomain, "Example.COM"},
+		{"web01.example.com.", FQDNSubdomain, "web01"},
+		{"example.com", FQDNSubdomain, "example.com"},
+		{"co.uk", FQDNDomain, "co.uk"},
+		{"10.0.0.1", FQDNSubdomain, "10.0.0.1"},
+		{"10.0.0.1", FQDNDomain, "10.0.0.1"},
+		{"10.0.0.1", FQDNHost, "10"},
+	}
+	for _, tt := range tests {
+		if got := StripDomain(tt.element, tt.mode); got != tt.want {
+			t.Errorf("StripDomain(%q, %q) = %q, want %q", tt.element, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalNumber(t *testing.T) {
+	tests := []struct {
+		element string
+		want    string
+		value   float64
+		ok      bool
+	}{
+		{"7", "7", 7, true},
+		{"007", "7", 7, true},
+		{" 7 ", "7", 7, true},
+		{"7.0", "7", 7, true},
+		{"-0.50", "-0.5", -0.5, true},
+		{"1e3", "1000", 1000, true},
+		{"9007199254740993", "9007199254740993", 9007199254740993, true},
+		{"NaN", "", 0, false},
+		{"Inf", "", 0, false},
+		{"0x1f", "", 0, false},
+		{"seven", "", 0, false},
+	}
+	for _, tt := range tests {
+		got, value, ok := CanonicalNumber(tt.element)
+		if got != tt.want || value != tt.value || ok != tt.ok {
+			t.Errorf("CanonicalNumber(%q) = %q, %v, %v, want %q, %v, %v", tt.element, got, value, ok, tt.want, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestStripLeadingZeros(t *testing.T) {
+	tests := map[string]string{
+		"INV-007":  "INV-7",
+		"0000":     "0",
+		"a01b002":  "a1b2",
+		"no digit": "no digit",
+	}
+	for element, want := range tests {
+		if got := StripLeadingZeros(element); got != want {
+			t.Errorf("StripLeadingZeros(%q) = %q, want %q", element, got, want)
+		}
+	}
+}
+
+func TestStripTimestamp(t *testing.T) {
+	tests := map[string]string{
+		"Jan  2 15:04:05 host sshd: ok":       "host sshd: ok",
+		"2006-01-02T15:04:05.000Z started":    "started",
+		"2006-01-02 15:04:05,000 started":     "started",
+		"[2006-01-02T15:04:05+01:00] started": "started",
+		"1136214245 started":                  "started",
+		"1136214245123 started":               "started",
+		"started at 2006-01-02T15:04:05Z":     "started at 2006-01-02T15:04:05Z",
+		"12345 is not a timestamp":            "12345 is not a timestamp",
+	}
+	for line, want := range tests {
+		if got := StripTimestamp(line); got != want {
+			t.Errorf("StripTimestamp(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestLogTemplate(t *testing.T) {
+	tests := map[string]string{
+		"user 42 logged in from 10.0.0.1:22":                  "user <num> logged in from <ip>",
+		"request 123e4567-e89b-12d3-a456-426614174000 failed": "request <uuid> failed",
+		"pointer 0xdeadbeef freed":                            "pointer <hex> freed",
+		"commit 3f2a9c1 pushed":                               "commit <hex> pushed",
+		"took 1.5 seconds":                                    "took <num> seconds",
+		"no variable parts":                                   "no variable parts",
+	}
+	for line, want := range tests {
+		if got := LogTemplate(line); got != want {
+			t.Errorf("LogTemplate(%q) = %q, want %q", line, got, want)
+		}
+	}
+}